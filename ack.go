@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/api"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+const ackUsage = `Usage:
+  k3s-health-checker alerts [-api URL]
+  k3s-health-checker ack [-api URL] [-author NAME] [-comment TEXT] -fingerprint FINGERPRINT
+  k3s-health-checker ack [-api URL] [-author NAME] [-comment TEXT] MATCHER...
+
+Matchers are written like 'node="rpi-1"' or 'name=~"rpi-.*"'. The API token,
+if the checker requires one, is read from $RELIABILITY_INFORMER_API_TOKEN.
+`
+
+// runAlerts lists the firing alerts of a running checker and returns the
+// exit code
+func runAlerts(args []string) int {
+	fs := flag.NewFlagSet("alerts", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, ackUsage) }
+	apiURL := fs.String("api", "http://localhost:8080", "address of the checker API")
+	if fs.Parse(args) != nil {
+		return 2
+	}
+
+	var firing []types.FiringAlert
+	if err := callAPI(http.MethodGet, *apiURL+"/api/alerts", nil, &firing); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FINGERPRINT\tSINCE\tACKED BY\tMESSAGE")
+	for _, f := range firing {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Fingerprint,
+			time.Since(f.Alert.StartsAt).Round(time.Second), f.AckedBy, f.Alert.Message)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runAck acknowledges firing alerts of a running checker and returns the
+// exit code
+func runAck(args []string) int {
+	fs := flag.NewFlagSet("ack", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, ackUsage) }
+	apiURL := fs.String("api", "http://localhost:8080", "address of the checker API")
+	fingerprint := fs.String("fingerprint", "", "fingerprint of the alert, as listed by the alerts command")
+	author := fs.String("author", os.Getenv("USER"), "who is working on the alerts")
+	comment := fs.String("comment", "", "shown when the alerts resolve")
+	if fs.Parse(args) != nil {
+		return 2
+	}
+	if (*fingerprint == "") == (fs.NArg() == 0) {
+		fs.Usage()
+		return 2
+	}
+
+	body, err := json.Marshal(api.AckRequest{
+		Fingerprint: *fingerprint,
+		Matchers:    fs.Args(),
+		AckedBy:     *author,
+		Comment:     *comment,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var acked []types.FiringAlert
+	if err := callAPI(http.MethodPost, *apiURL+"/api/acks", bytes.NewReader(body), &acked); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	for _, f := range acked {
+		fmt.Printf("Acknowledged %s\n", f.Fingerprint)
+	}
+	return 0
+}
@@ -4,19 +4,37 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/5iing/k8s-reliablity-informer/pkg/api"
 	"github.com/5iing/k8s-reliablity-informer/pkg/checker"
 	"github.com/5iing/k8s-reliablity-informer/pkg/config"
 	"github.com/5iing/k8s-reliablity-informer/pkg/notifier"
+	"github.com/5iing/k8s-reliablity-informer/pkg/silence"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "silence":
+			os.Exit(runSilence(os.Args[2:]))
+		case "alerts":
+			os.Exit(runAlerts(os.Args[2:]))
+		case "ack":
+			os.Exit(runAck(os.Args[2:]))
+		}
+	}
+
 	configFile := flag.String("config", "pkg/config/config.yaml", "path to config file")
 	flag.Parse()
 
@@ -32,6 +50,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(k8sConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating dynamic client: %v\n", err)
+		os.Exit(1)
+	}
+
 	appConfig, err := config.LoadConfig(*configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
@@ -41,10 +65,16 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	// validated by LoadConfig
+	discordTemplates, _ := appConfig.Notifiers.Discord.Templates.Compile()
+	consoleTemplates, _ := appConfig.Notifiers.Console.Templates.Compile()
+
 	var noti notifier.Notifier
+	templates := consoleTemplates
 
 	if appConfig.Notifiers.Discord.Enabled && appConfig.Notifiers.Discord.WebhookURL != "" {
 		noti = notifier.NewDiscord(appConfig.Notifiers.Discord.WebhookURL)
+		templates = discordTemplates
 		fmt.Println("Discord notifications enabled")
 	} else if appConfig.Notifiers.Console.Enabled {
 		noti = notifier.NewConsole()
@@ -54,12 +84,62 @@ func main() {
 		fmt.Println("No notifier configured, using console as fallback")
 	}
 
+	silences, err := silence.NewStore(appConfig.Silences.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading silences: %v\n", err)
+		os.Exit(1)
+	}
+
 	hc := checker.NewHealthChecker(ctx, client, *appConfig, noti)
+	hc.UseDynamicClient(dynamicClient)
+	hc.UseSilences(silences)
+	hc.UseTemplates("", templates)
+	if appConfig.Notifiers.Discord.Enabled {
+		for route, webhookURL := range appConfig.Notifiers.Discord.Routes {
+			hc.UseRoute(route, notifier.NewDiscord(webhookURL))
+			hc.UseTemplates(route, discordTemplates)
+			fmt.Printf("Discord route %s enabled\n", route)
+		}
+	}
 
 	fmt.Println(" Starting K8s Health Checker")
+	if appConfig.Cluster != "" {
+		fmt.Printf("   Cluster: %s\n", appConfig.Cluster)
+	}
 	fmt.Printf("   Pods: %v\n", appConfig.Checker.CheckPods)
 	fmt.Printf("   Nodes: %v\n", appConfig.Checker.CheckNodes)
 	fmt.Printf("   Deployments: %v\n", appConfig.Checker.CheckDeployments)
+	fmt.Printf("   DaemonSets: %v\n", appConfig.Checker.CheckDaemonSets)
+	fmt.Printf("   Jobs: %v\n", appConfig.Checker.CheckJobs)
+	fmt.Printf("   CronJobs: %v\n", appConfig.Checker.CheckCronJobs)
+	fmt.Printf("   Services: %v\n", appConfig.Checker.CheckServices)
+	fmt.Printf("   Storage: %v\n", appConfig.Checker.CheckStorage)
+	fmt.Printf("   Events: %v\n", appConfig.Checker.CheckEvents)
+	fmt.Printf("   HPAs: %v\n", appConfig.Checker.CheckHPAs)
+	fmt.Printf("   Quotas: %v\n", appConfig.Checker.CheckQuotas)
+	fmt.Printf("   PDBs: %v\n", appConfig.Checker.CheckPDBs)
+	fmt.Printf("   Certificates: %v\n", appConfig.Checker.CheckCertificates)
+	fmt.Printf("   Ingresses: %v\n", appConfig.Checker.CheckIngresses)
+	if len(appConfig.Checker.Enabled) > 0 {
+		fmt.Printf("   Enabled: %s\n", strings.Join(appConfig.Checker.Enabled, ", "))
+	}
+	if scope := appConfig.Checker.Scope; len(scope.IncludeNamespaces) > 0 || len(scope.ExcludeNamespaces) > 0 || scope.LabelSelector != "" {
+		fmt.Printf("   Scope: include %v, exclude %v, labels %q\n",
+			scope.IncludeNamespaces, scope.ExcludeNamespaces, scope.LabelSelector)
+	}
+	for _, cr := range appConfig.Checker.CustomResources {
+		fmt.Printf("   %s.%s: %s\n", cr.Resource, cr.Group, cr.ConditionType)
+	}
+	fmt.Printf("   CEL rules: %d\n", len(appConfig.Checker.CELRules))
+	fmt.Printf("   Inhibit rules: %d\n", len(appConfig.InhibitRules))
+	if grouping := appConfig.Grouping; len(grouping.By) > 0 {
+		fmt.Printf("   Grouping by %s\n", strings.Join(grouping.By, ", "))
+	}
+	fmt.Printf("   Escalations: %d\n", len(appConfig.Escalations))
+	fmt.Printf("   Silences: %d\n", len(silences.List()))
+	for _, w := range appConfig.MaintenanceWindows {
+		fmt.Printf("   Maintenance window %s: %q for %s %s\n", w.Name, w.Schedule, w.Duration, w.Timezone)
+	}
 
 	if err := hc.Start(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "Error starting health checker: %v\n", err)
@@ -68,6 +148,37 @@ func main() {
 
 	fmt.Println("Health checker started successfully")
 
+	if appConfig.API.Listen != "" {
+		server := &http.Server{
+			Addr:              appConfig.API.Listen,
+			Handler:           api.NewServer(silences, hc, appConfig.API.Token),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				fmt.Fprintf(os.Stderr, "API server failed: %v\n", err)
+			}
+		}()
+		defer server.Close()
+		fmt.Printf("API listening on %s\n", appConfig.API.Listen)
+		if appConfig.API.Token == "" && !loopback(appConfig.API.Listen) {
+			fmt.Fprintf(os.Stderr, "Warning: the API on %s has no token, anyone reaching it can silence alerts\n", appConfig.API.Listen)
+		}
+	}
+
 	<-ctx.Done()
 	fmt.Println("\nShutting down...")
 }
+
+// loopback reports whether the listen address only accepts local connections
+func loopback(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
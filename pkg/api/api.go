@@ -0,0 +1,176 @@
+// Package api serves the HTTP API used to manage the checker at runtime
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/silence"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+// SilenceRequest creates a silence. It ends at EndsAt, or Duration after it
+// starts when EndsAt is not set.
+type SilenceRequest struct {
+	Matchers  []string  `json:"matchers"`
+	CreatedBy string    `json:"created_by"`
+	Comment   string    `json:"comment"`
+	StartsAt  time.Time `json:"starts_at,omitempty"`
+	EndsAt    time.Time `json:"ends_at,omitempty"`
+	// e.g. "2h"
+	Duration string `json:"duration,omitempty"`
+}
+
+// AckRequest acknowledges the firing alert with Fingerprint, or every firing
+// alert matching all Matchers
+type AckRequest struct {
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	Matchers    []string `json:"matchers,omitempty"`
+	AckedBy     string   `json:"acked_by"`
+	Comment     string   `json:"comment"`
+}
+
+// Alerts are the firing alerts of the checker, implemented by
+// checker.HealthChecker
+type Alerts interface {
+	Firing() []types.FiringAlert
+	Acknowledge(fingerprint string, matchers []string, by, comment string) ([]types.FiringAlert, error)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server routes the API requests
+type Server struct {
+	silences *silence.Store
+	alerts   Alerts
+	// required as bearer token on every request unless empty
+	token string
+	mux   *http.ServeMux
+}
+
+// NewServer returns the API handler. With a token every request has to send
+// it as "Authorization: Bearer <token>".
+func NewServer(silences *silence.Store, alerts Alerts, token string) *Server {
+	s := &Server{
+		silences: silences,
+		alerts:   alerts,
+		token:    token,
+		mux:      http.NewServeMux(),
+	}
+	s.mux.HandleFunc("GET /api/silences", s.listSilences)
+	s.mux.HandleFunc("POST /api/silences", s.createSilence)
+	s.mux.HandleFunc("DELETE /api/silences/{id}", s.expireSilence)
+	s.mux.HandleFunc("GET /api/alerts", s.listAlerts)
+	s.mux.HandleFunc("POST /api/acks", s.acknowledge)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid API token"))
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) listSilences(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.silences.List())
+}
+
+func (s *Server) createSilence(w http.ResponseWriter, r *http.Request) {
+	var req SilenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	sil := silence.Silence{
+		Matchers:  req.Matchers,
+		CreatedBy: req.CreatedBy,
+		Comment:   req.Comment,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+	}
+	if sil.EndsAt.IsZero() {
+		if req.Duration == "" {
+			writeError(w, http.StatusBadRequest, errors.New("ends_at or duration is required"))
+			return
+		}
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid duration: %w", err))
+			return
+		}
+		start := sil.StartsAt
+		if start.IsZero() {
+			start = time.Now()
+		}
+		sil.EndsAt = start.Add(d)
+	}
+
+	created, err := s.silences.Add(sil)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) expireSilence(w http.ResponseWriter, r *http.Request) {
+	err := s.silences.Expire(r.PathValue("id"))
+	if errors.Is(err, silence.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listAlerts(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.alerts.Firing())
+}
+
+func (s *Server) acknowledge(w http.ResponseWriter, r *http.Request) {
+	var req AckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	acked, err := s.alerts.Acknowledge(req.Fingerprint, req.Matchers, req.AckedBy, req.Comment)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(acked) == 0 {
+		writeError(w, http.StatusNotFound, errors.New("no firing alert matches"))
+		return
+	}
+	writeJSON(w, http.StatusOK, acked)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Printf("Failed to write API response: %v\n", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
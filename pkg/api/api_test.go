@@ -0,0 +1,219 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/silence"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+// fakeAlerts acknowledges its firing alerts by fingerprint
+type fakeAlerts struct {
+	firing []types.FiringAlert
+}
+
+func (f *fakeAlerts) Firing() []types.FiringAlert {
+	return f.firing
+}
+
+func (f *fakeAlerts) Acknowledge(fingerprint string, matchers []string, by, comment string) ([]types.FiringAlert, error) {
+	acked := []types.FiringAlert{}
+	for i := range f.firing {
+		if f.firing[i].Fingerprint == fingerprint {
+			f.firing[i].AckedBy = by
+			f.firing[i].AckComment = comment
+			acked = append(acked, f.firing[i])
+		}
+	}
+	return acked, nil
+}
+
+func TestServer_Silences(t *testing.T) {
+	store, err := silence.NewStore("")
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	srv := httptest.NewServer(NewServer(store, &fakeAlerts{}, ""))
+	defer srv.Close()
+
+	body := `{"matchers": ["node=\"rpi-1\""], "created_by": "alice", "comment": "maintenance", "duration": "2h"}`
+	resp, err := http.Post(srv.URL+"/api/silences", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	var created silence.Silence
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", resp.StatusCode)
+	}
+	if created.ID == "" || created.CreatedBy != "alice" {
+		t.Errorf("Unexpected silence %+v", created)
+	}
+
+	resp, err = http.Get(srv.URL + "/api/silences")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	var list []silence.Silence
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	resp.Body.Close()
+	if len(list) != 1 || list[0].ID != created.ID {
+		t.Errorf("Expected the created silence to be listed, got %+v", list)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/api/silences/"+created.ID, nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", resp.StatusCode)
+	}
+
+	req, _ = http.NewRequest(http.MethodDelete, srv.URL+"/api/silences/missing", nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_CreateSilence_Invalid(t *testing.T) {
+	store, _ := silence.NewStore("")
+	srv := httptest.NewServer(NewServer(store, &fakeAlerts{}, ""))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "Malformed JSON", body: `{`},
+		{name: "No end", body: `{"matchers": ["node=\"rpi-1\""]}`},
+		{name: "Bad duration", body: `{"matchers": ["node=\"rpi-1\""], "duration": "soon"}`},
+		{name: "Bad matcher", body: `{"matchers": ["node"], "duration": "1h"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(srv.URL+"/api/silences", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("POST failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServer_Acknowledge(t *testing.T) {
+	store, _ := silence.NewStore("")
+	alerts := &fakeAlerts{firing: []types.FiringAlert{{
+		Fingerprint: "9c2f4e81a07b3d56",
+		Alert:       types.Alert{Level: types.AlertLevelCritical, Resource: types.ResourceTypeNode, Name: "rpi-1"},
+	}}}
+	srv := httptest.NewServer(NewServer(store, alerts, ""))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{
+			name:     "Firing alert",
+			body:     `{"fingerprint": "9c2f4e81a07b3d56", "acked_by": "alice", "comment": "on it"}`,
+			expected: http.StatusOK,
+		},
+		{
+			name:     "No firing alert",
+			body:     `{"fingerprint": "5d0e7a3c91f64b28", "acked_by": "alice"}`,
+			expected: http.StatusNotFound,
+		},
+		{
+			name:     "Malformed JSON",
+			body:     `{`,
+			expected: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(srv.URL+"/api/acks", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("POST failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, resp.StatusCode)
+			}
+		})
+	}
+
+	resp, err := http.Get(srv.URL + "/api/alerts")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var firing []types.FiringAlert
+	if err := json.NewDecoder(resp.Body).Decode(&firing); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(firing) != 1 || firing[0].AckedBy != "alice" {
+		t.Errorf("Expected the alert acknowledged by alice, got %+v", firing)
+	}
+}
+
+func TestServer_Token(t *testing.T) {
+	store, err := silence.NewStore("")
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	srv := httptest.NewServer(NewServer(store, &fakeAlerts{}, "s3cret"))
+	defer srv.Close()
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{name: "No token", status: http.StatusUnauthorized},
+		{name: "Wrong token", authorization: "Bearer guess", status: http.StatusUnauthorized},
+		{name: "Not a bearer token", authorization: "s3cret", status: http.StatusUnauthorized},
+		{name: "Valid token", authorization: "Bearer s3cret", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/silences", nil)
+			if err != nil {
+				t.Fatalf("NewRequest failed: %v", err)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("GET failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
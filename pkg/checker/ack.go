@@ -0,0 +1,86 @@
+package checker
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/matcher"
+	"github.com/5iing/k8s-reliablity-informer/pkg/message"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+// Firing returns the alerts currently firing, oldest first
+func (hc *HealthChecker) Firing() []types.FiringAlert {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	now := time.Now()
+	firing := make([]types.FiringAlert, 0, len(hc.firing))
+	for _, f := range hc.firing {
+		if now.Sub(f.lastSeen) > activeAlertTimeout {
+			continue
+		}
+		firing = append(firing, f.status())
+	}
+	sortFiring(firing)
+	return firing
+}
+
+// Acknowledge stops sending the firing alert with fingerprint, or every
+// firing alert matching all matchers, until it resolves. An alert changing
+// its level fires anew. It returns the acknowledged alerts.
+func (hc *HealthChecker) Acknowledge(fingerprint string, matchers []string, by, comment string) ([]types.FiringAlert, error) {
+	if (fingerprint == "") == (len(matchers) == 0) {
+		return nil, errors.New("either a fingerprint or matchers are required")
+	}
+	if by == "" {
+		return nil, errors.New("acknowledging needs an author")
+	}
+	compiled, err := matcher.ParseAll(matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	now := time.Now()
+	acked := []types.FiringAlert{}
+	for fp, f := range hc.firing {
+		if now.Sub(f.lastSeen) > activeAlertTimeout {
+			continue
+		}
+		if fingerprint != "" && fp != fingerprint {
+			continue
+		}
+		if fingerprint == "" && !matcher.MatchesAll(compiled, f.alert) {
+			continue
+		}
+
+		f.ackedBy = by
+		f.ackComment = comment
+		acked = append(acked, f.status())
+	}
+	sortFiring(acked)
+	return acked, nil
+}
+
+func (f *firingAlert) status() types.FiringAlert {
+	return types.FiringAlert{
+		Fingerprint: f.fingerprint,
+		Alert:       f.data(message.StatusFiring).Alert,
+		AckedBy:     f.ackedBy,
+		AckComment:  f.ackComment,
+	}
+}
+
+func sortFiring(firing []types.FiringAlert) {
+	sort.Slice(firing, func(i, j int) bool {
+		a, b := firing[i].Alert.StartsAt, firing[j].Alert.StartsAt
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return firing[i].Fingerprint < firing[j].Fingerprint
+	})
+}
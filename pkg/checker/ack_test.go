@@ -0,0 +1,78 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/message"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+func TestHealthChecker_Acknowledge(t *testing.T) {
+	notifier := &MockNotifier{}
+	hc := &HealthChecker{
+		notifier:     notifier,
+		alertHistory: make(map[string]time.Time),
+	}
+
+	nodeAlert := func(level string) types.Alert {
+		return types.Alert{
+			Level:    level,
+			Resource: types.ResourceTypeNode,
+			Name:     "rpi-1",
+			Message:  "Node is not ready",
+			Labels:   map[string]string{types.LabelNode: "rpi-1"},
+		}
+	}
+
+	hc.sendAlert(nodeAlert(types.AlertLevelError))
+	if len(notifier.GetAlerts()) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(notifier.GetAlerts()))
+	}
+
+	if _, err := hc.Acknowledge("", nil, "alice", ""); err == nil {
+		t.Error("Expected an acknowledgement without fingerprint or matchers to fail")
+	}
+	acked, err := hc.Acknowledge("", []string{`node="rpi-1"`}, "alice", "replacing the PSU")
+	if err != nil {
+		t.Fatalf("Acknowledge failed: %v", err)
+	}
+	errorAlert := nodeAlert(types.AlertLevelError)
+	if len(acked) != 1 || acked[0].Fingerprint != errorAlert.Fingerprint() {
+		t.Fatalf("Expected the node alert to be acknowledged, got %+v", acked)
+	}
+
+	// past the cooldown it is not repeated
+	for key := range hc.alertHistory {
+		hc.alertHistory[key] = time.Now().Add(-time.Hour)
+	}
+	hc.sendAlert(nodeAlert(types.AlertLevelError))
+	if len(notifier.GetAlerts()) != 1 {
+		t.Errorf("Expected the acknowledged alert not to be repeated, got %d alerts", len(notifier.GetAlerts()))
+	}
+
+	// a new level fires anew
+	hc.sendAlert(nodeAlert(types.AlertLevelCritical))
+	if len(notifier.GetAlerts()) != 2 {
+		t.Errorf("Expected the critical alert to be sent, got %d alerts", len(notifier.GetAlerts()))
+	}
+
+	resolved := hc.resolveFiring(time.Now().Add(2 * activeAlertTimeout))
+	if len(resolved) != 2 {
+		t.Fatalf("Expected both alerts to resolve, got %d", len(resolved))
+	}
+	for _, f := range resolved {
+		msg := message.Default(f.data(message.StatusResolved))
+		if f.alert.Level == types.AlertLevelError && !strings.HasSuffix(msg, "acknowledged by alice (replacing the PSU)") {
+			t.Errorf("Expected the resolved message to name alice, got %q", msg)
+		}
+		if f.alert.Level == types.AlertLevelCritical && strings.Contains(msg, "acknowledged") {
+			t.Errorf("Expected the critical alert not to be acknowledged, got %q", msg)
+		}
+	}
+
+	if acked, _ := hc.Acknowledge(errorAlert.Fingerprint(), nil, "alice", ""); len(acked) != 0 {
+		t.Errorf("Expected nothing to acknowledge once resolved, got %+v", acked)
+	}
+}
@@ -0,0 +1,135 @@
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type celRule struct {
+	rule    config.CELRule
+	program cel.Program
+	message *template.Template
+}
+
+// compileCELRules compiles the configured rules, grouped by resource
+func compileCELRules(rules []config.CELRule) (map[string][]celRule, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	compiled := make(map[string][]celRule)
+	for _, rule := range rules {
+		if rule.Resource == "" {
+			return nil, fmt.Errorf("CEL rule %q: resource is required", rule.Name)
+		}
+		if rule.Level != "" && !types.ValidLevel(rule.Level) {
+			return nil, fmt.Errorf("CEL rule %q: invalid level %q, must be critical, error, warning or info", rule.Name, rule.Level)
+		}
+
+		ast, issues := env.Compile(rule.Expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("CEL rule %q: %w", rule.Name, issues.Err())
+		}
+		if out := ast.OutputType(); out != cel.BoolType && out != cel.DynType {
+			return nil, fmt.Errorf("CEL rule %q: expression must return bool, got %s", rule.Name, out)
+		}
+
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("CEL rule %q: %w", rule.Name, err)
+		}
+
+		msg := rule.Message
+		if msg == "" {
+			msg = fmt.Sprintf("Rule %s matched", rule.Name)
+		}
+		tmpl, err := template.New(rule.Name).Parse(msg)
+		if err != nil {
+			return nil, fmt.Errorf("CEL rule %q: invalid message template: %w", rule.Name, err)
+		}
+
+		compiled[rule.Resource] = append(compiled[rule.Resource], celRule{
+			rule:    rule,
+			program: program,
+			message: tmpl,
+		})
+	}
+
+	return compiled, nil
+}
+
+// checkCELRules evaluates the rules configured for resource against obj.
+// Evaluation errors, e.g. a field missing from the object, count as no match.
+func (hc *HealthChecker) checkCELRules(resource string, obj interface{}) {
+	rules := hc.celRules[resource]
+	if len(rules) == 0 {
+		return
+	}
+
+	var content map[string]interface{}
+	switch o := obj.(type) {
+	case *unstructured.Unstructured:
+		content = o.Object
+	case runtime.Object:
+		var err error
+		content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+		if err != nil {
+			return
+		}
+	default:
+		return
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	name := accessor.GetName()
+	if accessor.GetNamespace() != "" {
+		name = fmt.Sprintf("%s/%s", accessor.GetNamespace(), name)
+	}
+
+	vars := map[string]interface{}{"object": content}
+	for _, r := range rules {
+		out, _, err := r.program.Eval(vars)
+		if err != nil {
+			continue
+		}
+		if matched, ok := out.Value().(bool); !ok || !matched {
+			continue
+		}
+
+		var msg bytes.Buffer
+		if err := r.message.Execute(&msg, vars); err != nil {
+			msg.Reset()
+			fmt.Fprintf(&msg, "Rule %s matched", r.rule.Name)
+		}
+
+		level := r.rule.Level
+		if level == "" {
+			level = types.AlertLevelWarning
+		}
+
+		hc.sendAlert(hc.prepare(types.Alert{
+			Level:    level,
+			Resource: resource,
+			Name:     name,
+			Message:  msg.String(),
+			// so rules matching one object and its checker's alerts are separate
+			Labels: map[string]string{types.LabelCheck: r.rule.Name},
+		}, obj))
+	}
+}
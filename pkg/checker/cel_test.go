@@ -0,0 +1,201 @@
+package checker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCompileCELRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    config.CELRule
+		wantErr bool
+	}{
+		{
+			name:    "Valid rule",
+			rule:    config.CELRule{Name: "ok", Resource: "deployment", Expression: "object.spec.replicas > 1"},
+			wantErr: false,
+		},
+		{
+			name:    "Syntax error",
+			rule:    config.CELRule{Name: "syntax", Resource: "deployment", Expression: "object.spec.replicas >"},
+			wantErr: true,
+		},
+		{
+			name:    "Non bool expression",
+			rule:    config.CELRule{Name: "string", Resource: "deployment", Expression: "'hello'"},
+			wantErr: true,
+		},
+		{
+			name:    "Missing resource",
+			rule:    config.CELRule{Name: "resource", Expression: "true"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid level",
+			rule:    config.CELRule{Name: "level", Resource: "deployment", Expression: "true", Level: "err"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid message template",
+			rule:    config.CELRule{Name: "template", Resource: "pod", Expression: "true", Message: "{{ .object"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileCELRules([]config.CELRule{tt.rule})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compileCELRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHealthChecker_checkCELRules(t *testing.T) {
+	rules, err := compileCELRules([]config.CELRule{
+		{
+			Name:       "half-ready",
+			Resource:   types.ResourceTypeDeployment,
+			Expression: "object.status.readyReplicas < object.spec.replicas / 2",
+			Level:      types.AlertLevelError,
+			Message:    "Only {{ .object.status.readyReplicas }}/{{ .object.spec.replicas }} replicas ready",
+		},
+	})
+	if err != nil {
+		t.Fatalf("compileCELRules failed: %v", err)
+	}
+
+	notifier := &MockNotifier{}
+	hc := &HealthChecker{
+		notifier:     notifier,
+		alertHistory: make(map[string]time.Time),
+		celRules:     rules,
+	}
+
+	newDeployment := func(replicas, ready int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: ready},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		resource string
+		obj      interface{}
+		expected string
+	}{
+		{
+			name:     "Less than half ready",
+			resource: types.ResourceTypeDeployment,
+			obj:      newDeployment(6, 2),
+			expected: "Only 2/6 replicas ready",
+		},
+		{
+			name:     "Most replicas ready",
+			resource: types.ResourceTypeDeployment,
+			obj:      newDeployment(6, 5),
+		},
+		{
+			name:     "Field missing from object",
+			resource: types.ResourceTypeDeployment,
+			obj:      newDeployment(6, 0),
+		},
+		{
+			name:     "No rules for resource",
+			resource: types.ResourceTypePod,
+			obj:      newDeployment(6, 2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier.ClearAlerts()
+			hc.alertHistory = make(map[string]time.Time)
+			hc.checkCELRules(tt.resource, tt.obj)
+
+			alerts := notifier.GetAlerts()
+			if tt.expected == "" {
+				if len(alerts) != 0 {
+					t.Errorf("Expected no alerts, got %+v", alerts)
+				}
+				return
+			}
+			if len(alerts) != 1 || !strings.Contains(alerts[0].Message, tt.expected) {
+				t.Errorf("Expected one alert containing %q, got %+v", tt.expected, alerts)
+			}
+		})
+	}
+}
+
+func TestHealthChecker_checkCELRules_SentSeparately(t *testing.T) {
+	rules, err := compileCELRules([]config.CELRule{
+		{Name: "single-replica", Resource: types.ResourceTypeDeployment, Expression: "object.spec.replicas == 1", Message: "Single replica"},
+		{Name: "none-ready", Resource: types.ResourceTypeDeployment, Expression: "!has(object.status.readyReplicas)", Message: "No replica ready"},
+	})
+	if err != nil {
+		t.Fatalf("compileCELRules failed: %v", err)
+	}
+
+	notifier := &MockNotifier{}
+	hc := &HealthChecker{
+		notifier:     notifier,
+		alertHistory: make(map[string]time.Time),
+		celRules:     rules,
+	}
+
+	replicas := int32(1)
+	hc.checkCELRules(types.ResourceTypeDeployment, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	})
+
+	alerts := notifier.GetAlerts()
+	if len(alerts) != 2 || len(hc.alertHistory) != 2 {
+		t.Fatalf("Expected an alert per matching rule, got %+v", alerts)
+	}
+	for _, expected := range []string{"Single replica", "No replica ready"} {
+		if !strings.Contains(alerts[0].Message+alerts[1].Message, expected) {
+			t.Errorf("Expected an alert containing %q, got %+v", expected, alerts)
+		}
+	}
+}
+
+func TestHealthChecker_Start_CELRuleResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		wantErr  bool
+	}{
+		{name: "Enabled checker", resource: types.ResourceTypeDeployment},
+		{name: "Disabled checker", resource: types.ResourceTypePod, wantErr: true},
+		{name: "Misspelled resource", resource: "deployments", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.AppConfig{}
+			cfg.Checker.CheckDeployments = true
+			cfg.Checker.CELRules = []config.CELRule{{Name: "half-ready", Resource: tt.resource, Expression: "true"}}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			hc := NewHealthChecker(ctx, fake.NewSimpleClientset(), cfg, &MockNotifier{})
+			if err := hc.Start(ctx); (err != nil) != tt.wantErr {
+				t.Errorf("Start() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package checker
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	networkinglisters "k8s.io/client-go/listers/networking/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	defaultCertWarningDays = 30
+	defaultCertErrorDays   = 7
+)
+
+func init() {
+	Register("certificates", func(cfg config.AppConfig) Checker {
+		warnDays := cfg.Checker.Certificates.WarningDays
+		if warnDays <= 0 {
+			warnDays = defaultCertWarningDays
+		}
+		errorDays := cfg.Checker.Certificates.ErrorDays
+		if errorDays <= 0 {
+			errorDays = defaultCertErrorDays
+		}
+		return &certificateChecker{warnDays: warnDays, errorDays: errorDays}
+	})
+}
+
+type certificateChecker struct {
+	warnDays  int
+	errorDays int
+	ingresses networkinglisters.IngressLister
+}
+
+func (c *certificateChecker) Name() string     { return "certificates" }
+func (c *certificateChecker) Resource() string { return types.ResourceTypeSecret }
+
+func (c *certificateChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	// ingress lister is used to name the ingresses serving a certificate
+	c.ingresses = src.Factory().Networking().V1().Ingresses().Lister()
+
+	return src.TLSSecrets().Informer(), nil
+}
+
+func (c *certificateChecker) Evaluate(obj interface{}) []types.Alert {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || secret.Type != corev1.SecretTypeTLS {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s/%s", secret.Namespace, secret.Name)
+
+	cert, err := earliestExpiring(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return []types.Alert{{
+			Level:    types.AlertLevelWarning,
+			Resource: types.ResourceTypeSecret,
+			Name:     name,
+			Message:  fmt.Sprintf("Unreadable certificate: %v", err),
+		}}
+	}
+
+	left := time.Until(cert.NotAfter)
+	days := int(left.Hours() / 24)
+
+	var level, msg string
+	switch {
+	case left <= 0:
+		level = types.AlertLevelCritical
+		msg = fmt.Sprintf("Certificate %s expired on %s", cert.Subject.CommonName, cert.NotAfter.Format(time.DateOnly))
+	case days < c.errorDays:
+		level = types.AlertLevelError
+		msg = fmt.Sprintf("Certificate %s expires in %d days on %s", cert.Subject.CommonName, days, cert.NotAfter.Format(time.DateOnly))
+	case days < c.warnDays:
+		level = types.AlertLevelWarning
+		msg = fmt.Sprintf("Certificate %s expires in %d days on %s", cert.Subject.CommonName, days, cert.NotAfter.Format(time.DateOnly))
+	default:
+		return nil
+	}
+
+	if ingresses := c.ingressesUsingSecret(secret.Namespace, secret.Name); len(ingresses) > 0 {
+		msg += fmt.Sprintf(", used by ingress: %s", strings.Join(ingresses, ", "))
+	}
+
+	return []types.Alert{{
+		Level:    level,
+		Resource: types.ResourceTypeSecret,
+		Name:     name,
+		Message:  msg,
+	}}
+}
+
+// earliestExpiring parses a PEM chain and returns the certificate that expires first
+func earliestExpiring(data []byte) (*x509.Certificate, error) {
+	var earliest *x509.Certificate
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		if earliest == nil || cert.NotAfter.Before(earliest.NotAfter) {
+			earliest = cert
+		}
+	}
+
+	if earliest == nil {
+		return nil, fmt.Errorf("no certificate found in %s", corev1.TLSCertKey)
+	}
+	return earliest, nil
+}
+
+func (c *certificateChecker) ingressesUsingSecret(namespace, secret string) []string {
+	if c.ingresses == nil {
+		return nil
+	}
+
+	ingresses, err := c.ingresses.Ingresses(namespace).List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, ing := range ingresses {
+		for _, tls := range ing.Spec.TLS {
+			if tls.SecretName == secret {
+				names = append(names, ing.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,143 @@
+package checker
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestCertPEM(t *testing.T, cn string, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newTLSSecret(name string, cert []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: cert},
+	}
+}
+
+func TestCertificateChecker_Evaluate(t *testing.T) {
+	c := &certificateChecker{warnDays: 30, errorDays: 7}
+
+	day := 24 * time.Hour
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		secret   *corev1.Secret
+		expected int
+		level    string
+	}{
+		{
+			name:     "Expired certificate",
+			secret:   newTLSSecret("expired", newTestCertPEM(t, "old.example.com", now.Add(-day))),
+			expected: 1,
+			level:    types.AlertLevelCritical,
+		},
+		{
+			name:     "Certificate within error days",
+			secret:   newTLSSecret("soon", newTestCertPEM(t, "soon.example.com", now.Add(3*day))),
+			expected: 1,
+			level:    types.AlertLevelError,
+		},
+		{
+			name:     "Certificate within warning days",
+			secret:   newTLSSecret("later", newTestCertPEM(t, "later.example.com", now.Add(20*day))),
+			expected: 1,
+			level:    types.AlertLevelWarning,
+		},
+		{
+			name: "Chain with an expiring intermediate",
+			secret: newTLSSecret("chain", append(
+				newTestCertPEM(t, "leaf.example.com", now.Add(80*day)),
+				newTestCertPEM(t, "intermediate", now.Add(2*day))...)),
+			expected: 1,
+			level:    types.AlertLevelError,
+		},
+		{
+			name:     "Valid certificate",
+			secret:   newTLSSecret("fresh", newTestCertPEM(t, "fresh.example.com", now.Add(80*day))),
+			expected: 0,
+		},
+		{
+			name:     "Garbage certificate",
+			secret:   newTLSSecret("garbage", []byte("not a certificate")),
+			expected: 1,
+			level:    types.AlertLevelWarning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := c.Evaluate(tt.secret)
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+				return
+			}
+			if tt.expected > 0 && alerts[0].Level != tt.level {
+				t.Errorf("Expected %s alert, got %s", tt.level, alerts[0].Level)
+			}
+		})
+	}
+}
+
+func TestCertificateChecker_Evaluate_Ingresses(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	secret := newTLSSecret("web-tls", newTestCertPEM(t, "web.example.com", time.Now().Add(48*time.Hour)))
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{{Hosts: []string{"web.example.com"}, SecretName: "web-tls"}},
+		},
+	}
+
+	c := &certificateChecker{warnDays: 30, errorDays: 7}
+	startChecker(ctx, t, c, fake.NewSimpleClientset(secret, ingress))
+
+	alerts := c.Evaluate(secret)
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(alerts))
+	}
+	if !strings.Contains(alerts[0].Message, "used by ingress: web") {
+		t.Errorf("Expected alert to name the ingress, got %q", alerts[0].Message)
+	}
+}
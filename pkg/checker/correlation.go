@@ -0,0 +1,84 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// nodesChecked reports whether node alerts are sent. Only then are pod and
+// deployment alerts caused by a not ready node left to the node alert.
+func nodesChecked(cfg config.AppConfig) bool {
+	return cfg.Checker.CheckNodes || containsString(cfg.Checker.Enabled, "nodes")
+}
+
+// nodeDown reports whether the named node is cached and not ready. A nil
+// lister never reports a node down.
+func nodeDown(nodes corelisters.NodeLister, name string) bool {
+	if nodes == nil || name == "" {
+		return false
+	}
+
+	node, err := nodes.Get(name)
+	if err != nil {
+		return false
+	}
+	return !isNodeReady(node)
+}
+
+// podsOnNode returns the pods scheduled on a node that have not terminated
+func podsOnNode(pods corelisters.PodLister, node string) []*corev1.Pod {
+	all, err := pods.List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+
+	var onNode []*corev1.Pod
+	for _, pod := range all {
+		if pod.Spec.NodeName != node ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		onNode = append(onNode, pod)
+	}
+	return onNode
+}
+
+// nodeImpact describes the workloads a down node takes with it, e.g.
+// "Impact: 3 pods of default/deployment/api, monitoring/pod/debug"
+func nodeImpact(pods []*corev1.Pod, owners *ownerResolver) string {
+	if len(pods) == 0 {
+		return ""
+	}
+
+	seen := make(map[string]bool)
+	var affected []string
+	for _, pod := range pods {
+		name := fmt.Sprintf("%s/pod/%s", pod.Namespace, pod.Name)
+		if w, ok := owners.workloadOf(pod); ok {
+			name = w.String()
+		}
+		if !seen[name] {
+			seen[name] = true
+			affected = append(affected, name)
+		}
+	}
+	sort.Strings(affected)
+
+	noun := "pods"
+	if len(pods) == 1 {
+		noun = "pod"
+	}
+
+	more := ""
+	if len(affected) > maxListedPods {
+		more = fmt.Sprintf(" and %d more", len(affected)-maxListedPods)
+		affected = affected[:maxListedPods]
+	}
+	return fmt.Sprintf("Impact: %d %s of %s%s", len(pods), noun, strings.Join(affected, ", "), more)
+}
@@ -0,0 +1,85 @@
+package checker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNodeCorrelation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	newNode := func(name string, ready corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready, Reason: "NodeStatusUnknown"}},
+			},
+		}
+	}
+	newPod := func(name, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", OwnerReferences: controllerRef("ReplicaSet", "api-7d9f")},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				}},
+			},
+		}
+	}
+	newDeployment := func(available int32) *appsv1.Deployment {
+		replicas := int32(3)
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: available},
+		}
+	}
+
+	down := newNode("rpi-1", corev1.ConditionUnknown)
+	client := fake.NewSimpleClientset(
+		down,
+		newNode("rpi-2", corev1.ConditionTrue),
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "api-7d9f", Namespace: "default", OwnerReferences: controllerRef("Deployment", "api"),
+		}},
+		newPod("api-1", "rpi-1"),
+		newPod("api-2", "rpi-1"),
+	)
+
+	nodes := &nodeChecker{}
+	pods := &podChecker{correlate: true}
+	deployments := &deploymentChecker{correlate: true}
+	startChecker(ctx, t, nodes, client)
+	startChecker(ctx, t, pods, client)
+	startChecker(ctx, t, deployments, client)
+
+	alerts := nodes.Evaluate(down)
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "Impact: 2 pods of default/deployment/api") {
+		t.Errorf("Expected node alert with impact, got %+v", alerts)
+	}
+
+	if alerts := pods.Evaluate(newPod("api-1", "rpi-1")); len(alerts) != 0 {
+		t.Errorf("Expected pod alerts on a down node to be suppressed, got %+v", alerts)
+	}
+	if alerts := pods.Evaluate(newPod("api-3", "rpi-2")); len(alerts) != 1 || !strings.Contains(alerts[0].Message, "1 pod: api-3") {
+		t.Errorf("Expected only the pod on the ready node, got %+v", alerts)
+	}
+
+	// two missing replicas are explained by the two pods on rpi-1
+	if alerts := deployments.Evaluate(newDeployment(1)); len(alerts) != 0 {
+		t.Errorf("Expected deployment alert to be suppressed, got %+v", alerts)
+	}
+	if alerts := deployments.Evaluate(newDeployment(0)); len(alerts) != 1 {
+		t.Errorf("Expected deployment alert, got %+v", alerts)
+	}
+}
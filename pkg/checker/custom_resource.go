@@ -0,0 +1,130 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+)
+
+// UseDynamicClient sets the client used to watch the configured custom
+// resources. It must be called before Start when any are configured.
+func (hc *HealthChecker) UseDynamicClient(client dynamic.Interface) {
+	hc.dynamicClient = client
+}
+
+// checkCustomResources makes sure the API server serves every configured
+// custom resource, as the informer of one it does not would never sync
+func (hc *HealthChecker) checkCustomResources() error {
+	for _, rule := range hc.config.Checker.CustomResources {
+		gvr := customResourceGVR(rule)
+		resources, err := hc.client.Discovery().ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+		if err != nil {
+			return fmt.Errorf("custom resource %s: %w", gvr.GroupResource(), err)
+		}
+
+		served := false
+		for _, resource := range resources.APIResources {
+			if resource.Name == gvr.Resource {
+				served = true
+				break
+			}
+		}
+		if !served {
+			return fmt.Errorf("custom resource %s: not served in %s, is its CRD installed?",
+				gvr.GroupResource(), gvr.GroupVersion())
+		}
+	}
+	return nil
+}
+
+func customResourceGVR(rule config.CustomResourceCheck) schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    rule.Group,
+		Version:  rule.Version,
+		Resource: rule.Resource,
+	}
+}
+
+// customResourceChecker alerts on a condition of a custom resource. One is
+// created per entry in config.Checker.CustomResources rather than registered.
+type customResourceChecker struct {
+	rule config.CustomResourceCheck
+}
+
+func (c *customResourceChecker) Name() string { return c.Resource() }
+
+func (c *customResourceChecker) Resource() string {
+	return customResourceGVR(c.rule).GroupResource().String()
+}
+
+func (c *customResourceChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	factory := src.DynamicFactory()
+	if factory == nil {
+		return nil, fmt.Errorf("custom resource checks need a dynamic client")
+	}
+	return factory.ForResource(customResourceGVR(c.rule)).Informer(), nil
+}
+
+func (c *customResourceChecker) Evaluate(o interface{}) []types.Alert {
+	obj, ok := o.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	rule := c.rule
+
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return nil
+	}
+
+	statuses := rule.UnhealthyStatuses
+	if len(statuses) == 0 {
+		statuses = []string{"False"}
+	}
+	level := rule.Level
+	if level == "" {
+		level = types.AlertLevelWarning
+	}
+
+	name := obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = fmt.Sprintf("%s/%s", obj.GetNamespace(), name)
+	}
+
+	for _, item := range conditions {
+		cond, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		condType, _, _ := unstructured.NestedString(cond, "type")
+		status, _, _ := unstructured.NestedString(cond, "status")
+		if condType != rule.ConditionType || !containsString(statuses, status) {
+			continue
+		}
+
+		reason, _, _ := unstructured.NestedString(cond, "reason")
+		message, _, _ := unstructured.NestedString(cond, "message")
+		return []types.Alert{{
+			Level:    level,
+			Resource: c.Resource(),
+			Name:     name,
+			Message:  fmt.Sprintf("%s is %s: %s: %s", condType, status, reason, message),
+		}}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
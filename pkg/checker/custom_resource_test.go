@@ -0,0 +1,133 @@
+package checker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newCertificate(name, status string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "cert-manager.io/v1",
+			"kind":       "Certificate",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+			},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":    "Ready",
+						"status":  status,
+						"reason":  "Failed",
+						"message": "issuer not ready",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCustomResourceChecker_Evaluate(t *testing.T) {
+	c := &customResourceChecker{rule: config.CustomResourceCheck{
+		Group:             "cert-manager.io",
+		Version:           "v1",
+		Resource:          "certificates",
+		ConditionType:     "Ready",
+		UnhealthyStatuses: []string{"False", "Unknown"},
+	}}
+
+	tests := []struct {
+		name     string
+		obj      *unstructured.Unstructured
+		expected int
+	}{
+		{name: "Not ready", obj: newCertificate("web", "False"), expected: 1},
+		{name: "Unknown", obj: newCertificate("api", "Unknown"), expected: 1},
+		{name: "Ready", obj: newCertificate("docs", "True"), expected: 0},
+		{
+			name: "No status",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "new", "namespace": "default"},
+			}},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := c.Evaluate(tt.obj)
+
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthChecker_Start_CustomResources(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cfg := config.AppConfig{}
+	cfg.Checker.CustomResources = []config.CustomResourceCheck{
+		{Group: "cert-manager.io", Version: "v1", Resource: "certificates", ConditionType: "Ready"},
+	}
+
+	hc := NewHealthChecker(ctx, fake.NewSimpleClientset(), cfg, &MockNotifier{})
+	if err := hc.Start(ctx); err == nil {
+		t.Fatal("Expected Start to fail without a dynamic client")
+	}
+
+	gvr := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "CertificateList"},
+		newCertificate("web", "False"))
+
+	// the CRD is not installed
+	hc = NewHealthChecker(ctx, fake.NewSimpleClientset(), cfg, &MockNotifier{})
+	hc.UseDynamicClient(dynamicClient)
+	if err := hc.Start(ctx); err == nil || !strings.Contains(err.Error(), "custom resource certificates.cert-manager.io") {
+		t.Fatalf("Expected Start to fail for a resource not served, got %v", err)
+	}
+
+	client := fake.NewSimpleClientset()
+	client.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "cert-manager.io/v1",
+		APIResources: []metav1.APIResource{{Name: "certificaterequests"}},
+	}}
+	hc = NewHealthChecker(ctx, client, cfg, &MockNotifier{})
+	hc.UseDynamicClient(dynamicClient)
+	if err := hc.Start(ctx); err == nil || !strings.Contains(err.Error(), "is its CRD installed?") {
+		t.Fatalf("Expected Start to fail for a resource missing from its group version, got %v", err)
+	}
+
+	client.Resources[0].APIResources = append(client.Resources[0].APIResources, metav1.APIResource{Name: "certificates"})
+	hc = NewHealthChecker(ctx, client, cfg, &MockNotifier{})
+	hc.UseDynamicClient(dynamicClient)
+	if err := hc.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	objs, err := hc.dynamicFactory.ForResource(gvr).Lister().List(labels.Everything())
+	if err != nil {
+		t.Fatalf("Failed to list certificates: %v", err)
+	}
+	if len(objs) != 1 {
+		t.Errorf("Expected 1 cached certificate, got %d", len(objs))
+	}
+}
@@ -0,0 +1,164 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func init() {
+	Register("daemonsets", func(cfg config.AppConfig) Checker { return &daemonSetChecker{} })
+}
+
+type daemonSetChecker struct {
+	nodes corelisters.NodeLister
+	pods  corelisters.PodLister
+}
+
+func (c *daemonSetChecker) Name() string     { return "daemonsets" }
+func (c *daemonSetChecker) Resource() string { return types.ResourceTypeDaemonSet }
+
+func (c *daemonSetChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	// pod and node listers are used to name the nodes missing a daemon pod
+	c.nodes = src.Factory().Core().V1().Nodes().Lister()
+	c.pods = src.Factory().Core().V1().Pods().Lister()
+
+	return src.Factory().Apps().V1().DaemonSets().Informer(), nil
+}
+
+func (c *daemonSetChecker) Evaluate(obj interface{}) []types.Alert {
+	ds, ok := obj.(*appsv1.DaemonSet)
+	if !ok {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s/%s", ds.Namespace, ds.Name)
+	var alerts []types.Alert
+
+	// unavailable daemon pods
+	if ds.Status.NumberUnavailable > 0 {
+		alerts = append(alerts, types.Alert{
+			Level:    types.AlertLevelWarning,
+			Resource: types.ResourceTypeDaemonSet,
+			Name:     name,
+			Message: fmt.Sprintf("Daemon pods unavailable: %d/%d",
+				ds.Status.NumberUnavailable, ds.Status.DesiredNumberScheduled),
+			Labels: map[string]string{types.LabelCheck: "unavailable"},
+		})
+	}
+
+	// running on nodes they should not be on
+	if ds.Status.NumberMisscheduled > 0 {
+		alerts = append(alerts, types.Alert{
+			Level:    types.AlertLevelWarning,
+			Resource: types.ResourceTypeDaemonSet,
+			Name:     name,
+			Message:  fmt.Sprintf("Daemon pods misscheduled: %d", ds.Status.NumberMisscheduled),
+			Labels:   map[string]string{types.LabelCheck: "misscheduled"},
+		})
+	}
+
+	// not scheduled everywhere
+	desired := ds.Status.DesiredNumberScheduled
+	current := ds.Status.CurrentNumberScheduled
+	if desired != current {
+		msg := fmt.Sprintf("Daemon pods scheduled: %d/%d", current, desired)
+		if missing := c.missingNodes(ds); len(missing) > 0 {
+			msg += fmt.Sprintf(", missing on nodes: %s", strings.Join(missing, ", "))
+		}
+		alerts = append(alerts, types.Alert{
+			Level:    types.AlertLevelWarning,
+			Resource: types.ResourceTypeDaemonSet,
+			Name:     name,
+			Message:  msg,
+			Labels:   map[string]string{types.LabelCheck: "missing"},
+		})
+	}
+
+	return alerts
+}
+
+// missingNodes returns the nodes that should run a pod of the daemonset
+// but don't. Eligibility only looks at the pod template's nodeSelector and
+// NoSchedule/NoExecute taints, node affinity is not evaluated.
+func (c *daemonSetChecker) missingNodes(ds *appsv1.DaemonSet) []string {
+	if c.nodes == nil || c.pods == nil {
+		return nil
+	}
+
+	nodes, err := c.nodes.List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return nil
+	}
+	pods, err := c.pods.Pods(ds.Namespace).List(selector)
+	if err != nil {
+		return nil
+	}
+
+	scheduled := make(map[string]bool)
+	for _, pod := range pods {
+		if isOwnedBy(pod.OwnerReferences, ds.UID) && pod.Spec.NodeName != "" {
+			scheduled[pod.Spec.NodeName] = true
+		}
+	}
+
+	nodeSelector := labels.SelectorFromSet(ds.Spec.Template.Spec.NodeSelector)
+	var missing []string
+	for _, node := range nodes {
+		if scheduled[node.Name] || !nodeSelector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		if !toleratesNode(ds.Spec.Template.Spec.Tolerations, node) {
+			continue
+		}
+		missing = append(missing, node.Name)
+	}
+	sort.Strings(missing)
+
+	return missing
+}
+
+func isOwnedBy(refs []metav1.OwnerReference, uid k8stypes.UID) bool {
+	for _, ref := range refs {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func toleratesNode(tolerations []corev1.Toleration, node *corev1.Node) bool {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package checker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDaemonSetChecker_Evaluate(t *testing.T) {
+	c := &daemonSetChecker{}
+
+	tests := []struct {
+		name     string
+		ds       *appsv1.DaemonSet
+		expected int
+	}{
+		{
+			name: "DaemonSet with unavailable pods",
+			ds: &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "flannel", Namespace: "kube-system"},
+				Status: appsv1.DaemonSetStatus{
+					DesiredNumberScheduled: 3,
+					CurrentNumberScheduled: 3,
+					NumberUnavailable:      1,
+				},
+			},
+			expected: 1,
+		},
+		{
+			name: "DaemonSet with misscheduled pods",
+			ds: &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "fluent-bit", Namespace: "logging"},
+				Status: appsv1.DaemonSetStatus{
+					DesiredNumberScheduled: 3,
+					CurrentNumberScheduled: 3,
+					NumberMisscheduled:     1,
+				},
+			},
+			expected: 1,
+		},
+		{
+			name: "DaemonSet not scheduled on every node",
+			ds: &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-exporter", Namespace: "monitoring"},
+				Status: appsv1.DaemonSetStatus{
+					DesiredNumberScheduled: 3,
+					CurrentNumberScheduled: 2,
+				},
+			},
+			expected: 1,
+		},
+		{
+			name: "Healthy DaemonSet",
+			ds: &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "svclb", Namespace: "kube-system"},
+				Status: appsv1.DaemonSetStatus{
+					DesiredNumberScheduled: 3,
+					CurrentNumberScheduled: 3,
+				},
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := c.Evaluate(tt.ds)
+
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+			}
+		})
+	}
+}
+
+func TestDaemonSetChecker_Evaluate_SentSeparately(t *testing.T) {
+	c := &daemonSetChecker{}
+	notifier := &MockNotifier{}
+	hc := &HealthChecker{
+		notifier:     notifier,
+		alertHistory: make(map[string]time.Time),
+	}
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "flannel", Namespace: "kube-system"},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 3,
+			CurrentNumberScheduled: 2,
+			NumberUnavailable:      1,
+			NumberMisscheduled:     1,
+		},
+	}
+	for _, alert := range c.Evaluate(ds) {
+		hc.sendAlert(alert)
+	}
+
+	sent := notifier.GetAlerts()
+	if len(sent) != 3 {
+		t.Fatalf("Expected 3 notifications, got %d: %v", len(sent), sent)
+	}
+	var missing bool
+	for _, alert := range sent {
+		if strings.Contains(alert.Message, "Daemon pods scheduled: 2/3") {
+			missing = true
+		}
+	}
+	if !missing {
+		t.Errorf("Expected the scheduling alert to be sent, got %v", sent)
+	}
+}
+
+func TestDaemonSetChecker_missingNodes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "flannel", Namespace: "kube-system", UID: "ds-uid"},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "flannel"}},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+				},
+			},
+		},
+	}
+
+	linux := map[string]string{"kubernetes.io/os": "linux"}
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "rpi-1", Labels: linux}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rpi-2", Labels: linux}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "win-1", Labels: map[string]string{"kubernetes.io/os": "windows"}}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "rpi-3", Labels: linux},
+			Spec: corev1.NodeSpec{
+				Taints: []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+			},
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "flannel-abcde",
+			Namespace:       "kube-system",
+			Labels:          map[string]string{"app": "flannel"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "flannel", UID: "ds-uid"}},
+		},
+		Spec: corev1.PodSpec{NodeName: "rpi-1"},
+	}
+
+	c := &daemonSetChecker{}
+	startChecker(ctx, t, c, fake.NewSimpleClientset(ds, pod, nodes[0], nodes[1], nodes[2], nodes[3]))
+
+	missing := c.missingNodes(ds)
+	if strings.Join(missing, ",") != "rpi-2" {
+		t.Errorf("Expected missing nodes [rpi-2], got %v", missing)
+	}
+}
@@ -0,0 +1,85 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func init() {
+	Register("deployments", func(cfg config.AppConfig) Checker {
+		return &deploymentChecker{correlate: nodesChecked(cfg)}
+	})
+}
+
+type deploymentChecker struct {
+	// leave replicas missing because of not ready nodes to the node alert
+	correlate bool
+	nodes     corelisters.NodeLister
+	pods      corelisters.PodLister
+	owners    *ownerResolver
+}
+
+func (c *deploymentChecker) Name() string     { return "deployments" }
+func (c *deploymentChecker) Resource() string { return types.ResourceTypeDeployment }
+
+func (c *deploymentChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	if c.correlate {
+		c.nodes = src.Factory().Core().V1().Nodes().Lister()
+		c.pods = src.Factory().Core().V1().Pods().Lister()
+		c.owners = newOwnerResolver(src.Factory())
+	}
+
+	return src.Factory().Apps().V1().Deployments().Informer(), nil
+}
+
+func (c *deploymentChecker) Evaluate(obj interface{}) []types.Alert {
+	deploy, ok := obj.(*appsv1.Deployment)
+	if !ok || deploy.Spec.Replicas == nil {
+		return nil
+	}
+
+	desired := *deploy.Spec.Replicas
+	available := deploy.Status.AvailableReplicas
+
+	if available < desired {
+		if c.onDownNodes(deploy) >= desired-available {
+			return nil
+		}
+
+		return []types.Alert{{
+			Level:    types.AlertLevelWarning,
+			Resource: types.ResourceTypeDeployment,
+			Name:     fmt.Sprintf("%s/%s", deploy.Namespace, deploy.Name),
+			Message:  fmt.Sprintf("Replicas not ready: %d/%d available", available, desired),
+		}}
+	}
+
+	return nil
+}
+
+// onDownNodes counts the deployment's pods on not ready nodes
+func (c *deploymentChecker) onDownNodes(deploy *appsv1.Deployment) int32 {
+	if c.nodes == nil || c.pods == nil || c.owners == nil {
+		return 0
+	}
+
+	pods, err := c.pods.Pods(deploy.Namespace).List(labels.Everything())
+	if err != nil {
+		return 0
+	}
+
+	self := workload{Kind: "Deployment", Namespace: deploy.Namespace, Name: deploy.Name}
+	var count int32
+	for _, pod := range pods {
+		if w, ok := c.owners.workloadOf(pod); ok && w == self && nodeDown(c.nodes, pod.Spec.NodeName) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,196 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/matcher"
+	"github.com/5iing/k8s-reliablity-informer/pkg/message"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+// how often firing alerts are checked for resolution and escalation
+const firingInterval = 30 * time.Second
+
+// firingAlert is an alert that passed inhibition, silences and maintenance
+// windows, tracked by fingerprint until it has not been seen for
+// activeAlertTimeout
+type firingAlert struct {
+	fingerprint string
+	alert       types.Alert
+	since       time.Time
+	lastSeen    time.Time
+	// escalation steps already notified
+	escalated int
+	// whether it was sent, so its resolution is worth a message
+	notified bool
+	// who acknowledged it, acknowledged alerts are not sent again
+	ackedBy    string
+	ackComment string
+}
+
+type escalationPolicy struct {
+	name     string
+	matchers []matcher.Matcher
+	steps    []escalationStep
+}
+
+type escalationStep struct {
+	after    time.Duration
+	receiver string
+}
+
+// escalation is a notification due to the receiver of a step
+type escalation struct {
+	receiver string
+	data     *message.Data
+}
+
+func compileEscalationPolicies(policies []config.EscalationPolicy) ([]escalationPolicy, error) {
+	compiled := make([]escalationPolicy, 0, len(policies))
+	for i, p := range policies {
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i+1)
+		}
+
+		matchers, err := matcher.ParseAll(p.Matchers)
+		if err != nil {
+			return nil, fmt.Errorf("escalation %s: %w", name, err)
+		}
+		if len(matchers) == 0 {
+			return nil, fmt.Errorf("escalation %s: matchers are required", name)
+		}
+		if len(p.Steps) == 0 {
+			return nil, fmt.Errorf("escalation %s: steps are required", name)
+		}
+
+		steps := make([]escalationStep, 0, len(p.Steps))
+		for j, step := range p.Steps {
+			if step.After <= 0 || step.Receiver == "" {
+				return nil, fmt.Errorf("escalation %s: step %d needs after and receiver", name, j+1)
+			}
+			steps = append(steps, escalationStep{after: step.After, receiver: step.Receiver})
+		}
+		sort.SliceStable(steps, func(a, b int) bool { return steps[a].after < steps[b].after })
+
+		compiled = append(compiled, escalationPolicy{name: name, matchers: matchers, steps: steps})
+	}
+	return compiled, nil
+}
+
+// fire records that alert is firing. The caller holds hc.mu.
+func (hc *HealthChecker) fire(fingerprint string, alert types.Alert, now time.Time) *firingAlert {
+	if hc.firing == nil {
+		hc.firing = make(map[string]*firingAlert)
+	}
+
+	// one that resolved in between fires anew
+	if f, ok := hc.firing[fingerprint]; ok && now.Sub(f.lastSeen) <= activeAlertTimeout {
+		f.alert = alert
+		f.lastSeen = now
+		return f
+	}
+	f := &firingAlert{fingerprint: fingerprint, alert: alert, since: now, lastSeen: now}
+	hc.firing[fingerprint] = f
+	return f
+}
+
+// resolveFiring drops and returns the firing alerts not seen for
+// activeAlertTimeout
+func (hc *HealthChecker) resolveFiring(now time.Time) []*firingAlert {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	var resolved []*firingAlert
+	for fingerprint, f := range hc.firing {
+		if now.Sub(f.lastSeen) > activeAlertTimeout {
+			delete(hc.firing, fingerprint)
+			resolved = append(resolved, f)
+		}
+	}
+	sort.Slice(resolved, func(i, j int) bool { return resolved[i].since.Before(resolved[j].since) })
+	return resolved
+}
+
+// watchFiring sends the messages for alerts that resolved and notifies the
+// receivers of escalation steps as alerts keep firing. Alerts are reported
+// on every resync, so this only needs to look at how long they have been
+// firing.
+func (hc *HealthChecker) watchFiring(ctx context.Context) {
+	ticker := time.NewTicker(firingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, f := range hc.resolveFiring(now) {
+				if f.notified && (f.ackedBy != "" || hc.config.Notifiers.SendResolved) {
+					d := f.data(message.StatusResolved)
+					hc.deliver(d.Route, hc.format(d.Route, d))
+				}
+			}
+			for _, e := range hc.dueEscalations(now) {
+				hc.deliver(e.receiver, hc.format(e.receiver, e.data))
+			}
+		}
+	}
+}
+
+// data returns what the notifications about f are formatted from
+func (f *firingAlert) data(status string) *message.Data {
+	d := &message.Data{
+		Alert:      f.alert,
+		Status:     status,
+		AckedBy:    f.ackedBy,
+		AckComment: f.ackComment,
+	}
+	d.StartsAt = f.since
+	if status == message.StatusResolved {
+		d.EndsAt = f.lastSeen
+	}
+	return d
+}
+
+// dueEscalations returns the steps firing alerts reached since the last
+// call. The first policy matching an alert applies, acknowledged alerts are
+// not escalated.
+func (hc *HealthChecker) dueEscalations(now time.Time) []escalation {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	fingerprints := make([]string, 0, len(hc.firing))
+	for fingerprint := range hc.firing {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+
+	var due []escalation
+	for _, fingerprint := range fingerprints {
+		f := hc.firing[fingerprint]
+		if f.ackedBy != "" {
+			continue
+		}
+		for _, policy := range hc.escalationPolicies {
+			if !matcher.MatchesAll(policy.matchers, f.alert) {
+				continue
+			}
+
+			firingFor := now.Sub(f.since)
+			for f.escalated < len(policy.steps) && policy.steps[f.escalated].after <= firingFor {
+				step := policy.steps[f.escalated]
+				d := f.data(message.StatusFiring)
+				d.Message = fmt.Sprintf("%s (firing for %s, escalated)", d.Message, firingFor.Round(time.Minute))
+				due = append(due, escalation{receiver: step.receiver, data: d})
+				f.escalated++
+			}
+			break
+		}
+	}
+	return due
+}
@@ -0,0 +1,137 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHealthChecker_dueEscalations(t *testing.T) {
+	policies, err := compileEscalationPolicies([]config.EscalationPolicy{{
+		Name:     "critical",
+		Matchers: []string{`level="critical"`},
+		Steps: []config.EscalationStep{
+			{After: time.Hour, Receiver: "lead"},
+			{After: 15 * time.Minute, Receiver: "oncall"},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("compileEscalationPolicies failed: %v", err)
+	}
+
+	hc := &HealthChecker{
+		notifier:           &MockNotifier{},
+		alertHistory:       make(map[string]time.Time),
+		escalationPolicies: policies,
+	}
+
+	nodeAlert := types.Alert{
+		Level:    types.AlertLevelCritical,
+		Resource: types.ResourceTypeNode,
+		Name:     "rpi-1",
+		Message:  "Node is not ready",
+	}
+	hc.sendAlert(nodeAlert)
+	hc.sendAlert(types.Alert{
+		Level:    types.AlertLevelWarning,
+		Resource: types.ResourceTypeNode,
+		Name:     "rpi-2",
+		Message:  "Node has disk pressure",
+	})
+
+	// keeps firing as the checker reports it on every resync
+	keepFiring := func(now time.Time) {
+		for _, f := range hc.firing {
+			f.lastSeen = now
+		}
+	}
+	start := time.Now()
+
+	tests := []struct {
+		name      string
+		after     time.Duration
+		receivers []string
+	}{
+		{name: "Before the first step", after: 10 * time.Minute},
+		{name: "First step", after: 20 * time.Minute, receivers: []string{"oncall"}},
+		{name: "First step only once", after: 30 * time.Minute},
+		{name: "Second step", after: 61 * time.Minute, receivers: []string{"lead"}},
+		{name: "No steps left", after: 3 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := start.Add(tt.after)
+			keepFiring(now)
+
+			due := hc.dueEscalations(now)
+			if len(due) != len(tt.receivers) {
+				t.Fatalf("Expected %d escalations, got %+v", len(tt.receivers), due)
+			}
+			for i, e := range due {
+				if e.receiver != tt.receivers[i] || e.data.Name != "rpi-1" {
+					t.Errorf("Expected rpi-1 escalated to %s, got %+v", tt.receivers[i], e)
+				}
+			}
+		})
+	}
+
+	// once resolved it starts over
+	if resolved := hc.resolveFiring(start.Add(4 * time.Hour)); len(resolved) != 2 {
+		t.Errorf("Expected both alerts to resolve, got %d", len(resolved))
+	}
+	if due := hc.dueEscalations(start.Add(4 * time.Hour)); len(due) != 0 {
+		t.Errorf("Expected no escalation for a resolved alert, got %+v", due)
+	}
+}
+
+func TestCompileEscalationPolicies_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy config.EscalationPolicy
+	}{
+		{
+			name:   "No matchers",
+			policy: config.EscalationPolicy{Steps: []config.EscalationStep{{After: time.Minute, Receiver: "oncall"}}},
+		},
+		{
+			name:   "No steps",
+			policy: config.EscalationPolicy{Matchers: []string{`level="critical"`}},
+		},
+		{
+			name: "Step without receiver",
+			policy: config.EscalationPolicy{
+				Matchers: []string{`level="critical"`},
+				Steps:    []config.EscalationStep{{After: time.Minute}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := compileEscalationPolicies([]config.EscalationPolicy{tt.policy}); err == nil {
+				t.Error("Expected compileEscalationPolicies to fail")
+			}
+		})
+	}
+}
+
+func TestHealthChecker_Start_UnknownEscalationReceiver(t *testing.T) {
+	cfg := config.AppConfig{}
+	cfg.Escalations = []config.EscalationPolicy{{
+		Matchers: []string{`level="critical"`},
+		Steps:    []config.EscalationStep{{After: 15 * time.Minute, Receiver: "oncall"}},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hc := NewHealthChecker(ctx, fake.NewSimpleClientset(), cfg, &MockNotifier{})
+	if err := hc.Start(ctx); err == nil {
+		t.Error("Expected Start to fail for a receiver without a route")
+	}
+}
@@ -0,0 +1,217 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+func init() {
+	Register("events", func(cfg config.AppConfig) Checker {
+		return &eventChecker{config: cfg}
+	})
+}
+
+type eventChecker struct {
+	config    config.AppConfig
+	startedAt time.Time
+
+	// the scope applies to the object an event is about, looked up by kind
+	// only when a label selector needs its labels
+	inScope func(obj interface{}) bool
+	objects map[string]func(namespace, name string) (interface{}, error)
+
+	// last occurrence alerted on per event, so resyncs of an unchanged
+	// event are not sent again once the alert cooldown passed
+	mu       sync.Mutex
+	notified map[k8stypes.UID]eventOccurrence
+}
+
+type eventOccurrence struct {
+	count    int32
+	lastSeen time.Time
+}
+
+func (c *eventChecker) Name() string     { return "events" }
+func (c *eventChecker) Resource() string { return types.ResourceTypeEvent }
+
+func (c *eventChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	c.startedAt = time.Now()
+	c.inScope = src.InScope
+
+	// without a selector the scope needs no more than the namespace of the
+	// involved object, so the other resources are not cached for it
+	if c.config.Checker.Scope.LabelSelector == "" {
+		return src.Factory().Core().V1().Events().Informer(), nil
+	}
+
+	core, apps, batch := src.Factory().Core().V1(), src.Factory().Apps().V1(), src.Factory().Batch().V1()
+	pods, nodes, claims := core.Pods().Lister(), core.Nodes().Lister(), core.PersistentVolumeClaims().Lister()
+	services := core.Services().Lister()
+	deployments, replicaSets := apps.Deployments().Lister(), apps.ReplicaSets().Lister()
+	statefulSets, daemonSets := apps.StatefulSets().Lister(), apps.DaemonSets().Lister()
+	jobs, cronJobs := batch.Jobs().Lister(), batch.CronJobs().Lister()
+	c.objects = map[string]func(namespace, name string) (interface{}, error){
+		"Pod":                   func(ns, name string) (interface{}, error) { return pods.Pods(ns).Get(name) },
+		"Node":                  func(_, name string) (interface{}, error) { return nodes.Get(name) },
+		"PersistentVolumeClaim": func(ns, name string) (interface{}, error) { return claims.PersistentVolumeClaims(ns).Get(name) },
+		"Service":               func(ns, name string) (interface{}, error) { return services.Services(ns).Get(name) },
+		"Deployment":            func(ns, name string) (interface{}, error) { return deployments.Deployments(ns).Get(name) },
+		"ReplicaSet":            func(ns, name string) (interface{}, error) { return replicaSets.ReplicaSets(ns).Get(name) },
+		"StatefulSet":           func(ns, name string) (interface{}, error) { return statefulSets.StatefulSets(ns).Get(name) },
+		"DaemonSet":             func(ns, name string) (interface{}, error) { return daemonSets.DaemonSets(ns).Get(name) },
+		"Job":                   func(ns, name string) (interface{}, error) { return jobs.Jobs(ns).Get(name) },
+		"CronJob":               func(ns, name string) (interface{}, error) { return cronJobs.CronJobs(ns).Get(name) },
+	}
+
+	return src.Factory().Core().V1().Events().Informer(), nil
+}
+
+func (c *eventChecker) Evaluate(obj interface{}) []types.Alert {
+	event, ok := obj.(*corev1.Event)
+	if !ok || event.Type != corev1.EventTypeWarning {
+		return nil
+	}
+
+	// events already in the cache at startup are history, not news
+	if !c.startedAt.IsZero() && eventLastSeen(event).Before(c.startedAt) {
+		return nil
+	}
+
+	involved := event.InvolvedObject
+	cfg := c.config.Checker.Events
+	if !matchesFilter(event.Reason, cfg.IncludeReasons, cfg.ExcludeReasons) ||
+		!matchesFilter(involved.Kind, cfg.IncludeKinds, cfg.ExcludeKinds) ||
+		!matchesFilter(involved.Namespace, cfg.IncludeNamespaces, cfg.ExcludeNamespaces) {
+		return nil
+	}
+
+	if c.inScope != nil && !c.inScope(c.involvedObject(involved)) {
+		return nil
+	}
+
+	count := eventCount(event)
+	if count < cfg.MinCount {
+		return nil
+	}
+	if !c.occurredAgain(event, count) {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s/%s", strings.ToLower(involved.Kind), involved.Name)
+	if involved.Namespace != "" {
+		name = fmt.Sprintf("%s/%s", involved.Namespace, name)
+	}
+
+	msg := fmt.Sprintf("%s: %s", event.Reason, strings.TrimSpace(event.Message))
+	if count > 1 {
+		msg += fmt.Sprintf(" (x%d)", count)
+	}
+
+	alert := types.Alert{
+		Level:    types.AlertLevelWarning,
+		Resource: types.ResourceTypeEvent,
+		Name:     name,
+		Message:  msg,
+		// events with different reasons about one object are separate alerts
+		Labels: map[string]string{types.LabelReason: event.Reason},
+	}
+	// so node inhibit rules and silences cover the events of the node
+	if involved.Kind == "Node" {
+		alert.Labels[types.LabelNode] = involved.Name
+	}
+	return []types.Alert{alert}
+}
+
+// involvedObject returns the object an event is about, or one with only its
+// name and namespace, and so no labels, when it is not cached or no lister
+// was set up for it
+func (c *eventChecker) involvedObject(ref corev1.ObjectReference) interface{} {
+	if get, ok := c.objects[ref.Kind]; ok {
+		if obj, err := get(ref.Namespace, ref.Name); err == nil {
+			return obj
+		}
+	}
+	return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}}
+}
+
+// occurredAgain records the occurrence of event and reports whether it is
+// newer than the last one alerted on
+func (c *eventChecker) occurredAgain(event *corev1.Event, count int32) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.notified == nil {
+		c.notified = make(map[k8stypes.UID]eventOccurrence)
+	}
+
+	current := eventOccurrence{count: count, lastSeen: eventLastSeen(event)}
+	last, exists := c.notified[event.UID]
+	if exists && current.count <= last.count && !current.lastSeen.After(last.lastSeen) {
+		return false
+	}
+	c.notified[event.UID] = current
+	return true
+}
+
+func (c *eventChecker) Forget(obj interface{}) {
+	if event, ok := obj.(*corev1.Event); ok {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		delete(c.notified, event.UID)
+	}
+}
+
+// eventCount returns how often the event occurred, from the series when the
+// event has been aggregated into one
+func eventCount(event *corev1.Event) int32 {
+	count := event.Count
+	if event.Series != nil && event.Series.Count > count {
+		count = event.Series.Count
+	}
+	if count < 1 {
+		count = 1
+	}
+	return count
+}
+
+func eventLastSeen(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// matchesFilter reports whether value is in include (or include is empty) and
+// not in exclude
+func matchesFilter(value string, include, exclude []string) bool {
+	for _, ex := range exclude {
+		if strings.EqualFold(ex, value) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, in := range include {
+		if strings.EqualFold(in, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEventChecker_Evaluate(t *testing.T) {
+	c := &eventChecker{startedAt: time.Now().Add(-time.Minute)}
+	c.config.Checker.Events.ExcludeReasons = []string{"Unhealthy"}
+	c.config.Checker.Events.ExcludeNamespaces = []string{"kube-system"}
+	c.config.Checker.Events.MinCount = 2
+
+	newEvent := func(eventType, reason, kind, namespace string, count int32, lastSeen time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: "ev", Namespace: namespace, UID: k8stypes.UID(reason + "/" + namespace)},
+			InvolvedObject: corev1.ObjectReference{
+				Kind:      kind,
+				Name:      "api-7d9f",
+				Namespace: namespace,
+			},
+			Type:          eventType,
+			Reason:        reason,
+			Message:       "something went wrong",
+			Count:         count,
+			LastTimestamp: metav1.NewTime(lastSeen),
+		}
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name     string
+		event    *corev1.Event
+		expected int
+	}{
+		{
+			name:     "Repeated FailedMount warning",
+			event:    newEvent(corev1.EventTypeWarning, "FailedMount", "Pod", "default", 5, now),
+			expected: 1,
+		},
+		{
+			name:     "Normal event",
+			event:    newEvent(corev1.EventTypeNormal, "Pulled", "Pod", "default", 5, now),
+			expected: 0,
+		},
+		{
+			name:     "Excluded reason",
+			event:    newEvent(corev1.EventTypeWarning, "Unhealthy", "Pod", "default", 5, now),
+			expected: 0,
+		},
+		{
+			name:     "Excluded namespace",
+			event:    newEvent(corev1.EventTypeWarning, "BackOff", "Pod", "kube-system", 5, now),
+			expected: 0,
+		},
+		{
+			name:     "Below min count",
+			event:    newEvent(corev1.EventTypeWarning, "FailedScheduling", "Pod", "apps", 1, now),
+			expected: 0,
+		},
+		{
+			name:     "Seen before startup",
+			event:    newEvent(corev1.EventTypeWarning, "NodeNotReady", "Node", "", 5, now.Add(-time.Hour)),
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := c.Evaluate(tt.event)
+
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+			}
+		})
+	}
+}
+
+func TestEventChecker_Evaluate_NewOccurrences(t *testing.T) {
+	c := &eventChecker{startedAt: time.Now().Add(-time.Minute)}
+	notifier := &MockNotifier{}
+	hc := &HealthChecker{
+		notifier:     notifier,
+		alertHistory: make(map[string]time.Time),
+	}
+	send := func(event *corev1.Event) {
+		for _, alert := range c.Evaluate(event) {
+			hc.sendAlert(alert)
+		}
+	}
+
+	newEvent := func(uid, reason string, count int32) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: uid, Namespace: "default", UID: k8stypes.UID(uid)},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-7d9f-x2k4", Namespace: "default"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        "something went wrong",
+			Count:          count,
+			LastTimestamp:  metav1.NewTime(time.Now()),
+		}
+	}
+	mount := newEvent("mount", "FailedMount", 3)
+	backOff := newEvent("backoff", "BackOff", 3)
+
+	// both reasons are sent for the same pod
+	send(mount)
+	send(backOff)
+	if len(notifier.GetAlerts()) != 2 {
+		t.Fatalf("Expected both events to be sent, got %d", len(notifier.GetAlerts()))
+	}
+
+	// a resync past the cooldown does not repeat an unchanged event
+	for key := range hc.alertHistory {
+		hc.alertHistory[key] = time.Now().Add(-time.Hour)
+	}
+	send(mount)
+	if len(notifier.GetAlerts()) != 2 {
+		t.Errorf("Expected the unchanged event not to be sent again, got %d", len(notifier.GetAlerts()))
+	}
+
+	// occurring again does
+	mount.Count = 4
+	send(mount)
+	if len(notifier.GetAlerts()) != 3 {
+		t.Errorf("Expected the repeated event to be sent, got %d", len(notifier.GetAlerts()))
+	}
+
+	alerts := c.Evaluate(newEvent("other", "FailedMount", 3))
+	if len(alerts) != 1 || alerts[0].Labels[types.LabelReason] != "FailedMount" {
+		t.Errorf("Expected an alert labelled with the reason, got %+v", alerts)
+	}
+	c.Forget(mount)
+	if alerts := c.Evaluate(mount); len(alerts) != 1 {
+		t.Errorf("Expected a forgotten event to be evaluated anew, got %+v", alerts)
+	}
+}
+
+func TestEventChecker_Evaluate_Scope(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cfg := config.AppConfig{}
+	cfg.Checker.Scope.LabelSelector = "team=payments"
+	s, err := newScope(cfg)
+	if err != nil {
+		t.Fatalf("newScope failed: %v", err)
+	}
+
+	newPod := func(name string, labels, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "default", Labels: labels, Annotations: annotations,
+		}}
+	}
+	payments := map[string]string{"team": "payments"}
+
+	c := &eventChecker{config: cfg}
+	startChecker(ctx, t, c, fake.NewSimpleClientset(
+		newPod("api", payments, nil),
+		newPod("web", map[string]string{"team": "web"}, nil),
+		newPod("debug", payments, map[string]string{IgnoreAnnotation: "true"}),
+	))
+	c.inScope = s.matches
+	c.startedAt = time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		kind     string
+		object   string
+		expected int
+	}{
+		{name: "Selected pod", kind: "Pod", object: "api", expected: 1},
+		{name: "Pod not selected", kind: "Pod", object: "web", expected: 0},
+		{name: "Ignored pod", kind: "Pod", object: "debug", expected: 0},
+		{name: "Pod not cached has no labels", kind: "Pod", object: "gone", expected: 0},
+		{name: "Node", kind: "Node", object: "rpi-1", expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace := "default"
+			if tt.kind == "Node" {
+				namespace = ""
+			}
+			event := &corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: tt.object, Namespace: "default", UID: k8stypes.UID(tt.object)},
+				InvolvedObject: corev1.ObjectReference{Kind: tt.kind, Name: tt.object, Namespace: namespace},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "BackOff",
+				LastTimestamp:  metav1.NewTime(time.Now()),
+			}
+			if alerts := c.Evaluate(event); len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %+v", tt.expected, alerts)
+			}
+		})
+	}
+
+	// without a selector the involved objects are not looked up
+	c = &eventChecker{}
+	startChecker(ctx, t, c, fake.NewSimpleClientset())
+	if c.objects != nil {
+		t.Errorf("Expected no listers without a label selector, got %d", len(c.objects))
+	}
+}
+
+func TestMatchesFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		include  []string
+		exclude  []string
+		expected bool
+	}{
+		{name: "No filters", value: "BackOff", expected: true},
+		{name: "Included", value: "BackOff", include: []string{"backoff"}, expected: true},
+		{name: "Not included", value: "BackOff", include: []string{"FailedMount"}, expected: false},
+		{name: "Excluded wins", value: "BackOff", include: []string{"BackOff"}, exclude: []string{"BackOff"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesFilter(tt.value, tt.include, tt.exclude); got != tt.expected {
+				t.Errorf("matchesFilter() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
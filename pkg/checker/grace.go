@@ -0,0 +1,48 @@
+package checker
+
+import (
+	"sync"
+	"time"
+)
+
+// graceTracker remembers since when a condition that needs a grace period
+// has been unhealthy
+type graceTracker struct {
+	mu    sync.Mutex
+	since map[string]time.Time
+}
+
+// unhealthyFor marks key as unhealthy and returns how long it has been so
+func (g *graceTracker) unhealthyFor(key string) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.since == nil {
+		g.since = make(map[string]time.Time)
+	}
+
+	since, exists := g.since[key]
+	if !exists {
+		since = time.Now()
+		g.since[key] = since
+	}
+	return time.Since(since)
+}
+
+func (g *graceTracker) clear(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.since, key)
+}
+
+// setSince backdates key, for tests
+func (g *graceTracker) setSince(key string, since time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.since == nil {
+		g.since = make(map[string]time.Time)
+	}
+	g.since[key] = since
+}
@@ -0,0 +1,151 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/message"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+const (
+	defaultGroupWait     = 30 * time.Second
+	defaultGroupInterval = 5 * time.Minute
+)
+
+// severity of the levels, used to pick the emoji of a grouped notification
+var levelRank = map[string]int{
+	types.AlertLevelInfo:     1,
+	types.AlertLevelWarning:  2,
+	types.AlertLevelError:    3,
+	types.AlertLevelCritical: 4,
+}
+
+// grouper batches alerts with the same values for the by labels, like
+// Alertmanager grouping. The first alert of a group waits groupWait for
+// others, alerts added after a group was sent go out every groupInterval,
+// and a group with nothing added for an interval is dropped.
+type grouper struct {
+	by       []string
+	wait     time.Duration
+	interval time.Duration
+	// formats an alert for a route and sends a message through its notifier
+	format func(route string, d *message.Data) string
+	send   func(route, msg string)
+
+	groups map[string]*alertGroup
+	mu     sync.Mutex
+}
+
+type alertGroup struct {
+	route  string
+	labels []string
+	// alerts waiting to be sent, by alert key
+	pending map[string]*message.Data
+}
+
+func newGrouper(by []string, wait, interval time.Duration,
+	format func(route string, d *message.Data) string, send func(route, msg string)) *grouper {
+	if wait <= 0 {
+		wait = defaultGroupWait
+	}
+	if interval <= 0 {
+		interval = defaultGroupInterval
+	}
+	return &grouper{
+		by:       by,
+		wait:     wait,
+		interval: interval,
+		format:   format,
+		send:     send,
+		groups:   make(map[string]*alertGroup),
+	}
+}
+
+// add queues alert in its group, starting the group when it is the first
+func (g *grouper) add(key string, d *message.Data) {
+	labels := make([]string, 0, len(g.by))
+	for _, name := range g.by {
+		labels = append(labels, fmt.Sprintf("%s=%s", name, d.Label(name)))
+	}
+	// alerts routed elsewhere never share a notification
+	groupKey := d.Route + "|" + strings.Join(labels, ",")
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	group, ok := g.groups[groupKey]
+	if !ok {
+		group = &alertGroup{
+			route:   d.Route,
+			labels:  labels,
+			pending: make(map[string]*message.Data),
+		}
+		g.groups[groupKey] = group
+		time.AfterFunc(g.wait, func() { g.flush(groupKey) })
+	}
+	group.pending[key] = d
+}
+
+// flush sends the pending alerts of a group and schedules the next flush,
+// or drops the group when nothing is pending
+func (g *grouper) flush(groupKey string) {
+	g.mu.Lock()
+	group, ok := g.groups[groupKey]
+	if !ok {
+		g.mu.Unlock()
+		return
+	}
+	if len(group.pending) == 0 {
+		delete(g.groups, groupKey)
+		g.mu.Unlock()
+		return
+	}
+
+	pending := group.pending
+	group.pending = make(map[string]*message.Data)
+	time.AfterFunc(g.interval, func() { g.flush(groupKey) })
+	g.mu.Unlock()
+
+	g.send(group.route, g.message(group, pending))
+}
+
+// message formats the alerts of a group as one notification, a single
+// alert as usual
+func (g *grouper) message(group *alertGroup, alerts map[string]*message.Data) string {
+	keys := make([]string, 0, len(alerts))
+	highest := types.Alert{}
+	for key, d := range alerts {
+		keys = append(keys, key)
+		if levelRank[d.Level] > levelRank[highest.Level] {
+			highest.Level = d.Level
+		}
+	}
+
+	if len(keys) == 1 {
+		return g.format(group.route, alerts[keys[0]])
+	}
+
+	// most severe first
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := alerts[keys[i]], alerts[keys[j]]
+		if levelRank[a.Level] != levelRank[b.Level] {
+			return levelRank[a.Level] > levelRank[b.Level]
+		}
+		return keys[i] < keys[j]
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %d alerts for %s:", highest.GetEmoji(), len(keys), strings.Join(group.labels, ", "))
+	for i, key := range keys {
+		if i == maxSummarizedAlerts {
+			fmt.Fprintf(&b, "\n... and %d more", len(keys)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n%s", g.format(group.route, alerts[key]))
+	}
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/message"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+func TestGrouper(t *testing.T) {
+	sent := make(chan string, 10)
+	g := newGrouper([]string{types.LabelNamespace, types.LabelResource},
+		50*time.Millisecond, 100*time.Millisecond,
+		func(route string, d *message.Data) string { return message.Default(d) },
+		func(route, msg string) { sent <- msg })
+
+	podAlert := func(namespace, name, level string) types.Alert {
+		return types.Alert{
+			Level:    level,
+			Resource: types.ResourceTypePod,
+			Name:     namespace + "/" + name,
+			Message:  "Container is in CrashLoopBackOff",
+			Labels:   map[string]string{types.LabelNamespace: namespace},
+		}
+	}
+	add := func(alert types.Alert) {
+		g.add(alert.Fingerprint(), &message.Data{Alert: alert, Status: message.StatusFiring})
+	}
+
+	receive := func() string {
+		select {
+		case msg := <-sent:
+			return msg
+		case <-time.After(time.Second):
+			t.Fatal("Expected a notification")
+			return ""
+		}
+	}
+
+	add(podAlert("default", "api-1", types.AlertLevelWarning))
+	add(podAlert("default", "api-2", types.AlertLevelError))
+	add(podAlert("monitoring", "grafana", types.AlertLevelError))
+
+	got := []string{receive(), receive()}
+	var grouped, single string
+	for _, msg := range got {
+		if strings.Contains(msg, "monitoring") {
+			single = msg
+		} else {
+			grouped = msg
+		}
+	}
+	if !strings.HasPrefix(grouped, "❌ 2 alerts for namespace=default, resource=pod:") {
+		t.Errorf("Expected the default pods in one notification, got %q", grouped)
+	}
+	if strings.Index(grouped, "api-2") > strings.Index(grouped, "api-1") {
+		t.Errorf("Expected the error before the warning, got %q", grouped)
+	}
+	if single != "❌ [pod] monitoring/grafana: Container is in CrashLoopBackOff" {
+		t.Errorf("Expected a group of one to be sent as usual, got %q", single)
+	}
+
+	// added after the first notification, sent with the next interval
+	add(podAlert("default", "api-3", types.AlertLevelError))
+	add(podAlert("default", "api-4", types.AlertLevelError))
+	start := time.Now()
+	if msg := receive(); !strings.HasPrefix(msg, "❌ 2 alerts") || strings.Contains(msg, "api-1") {
+		t.Errorf("Expected only the new alerts, got %q", msg)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected the new alerts to wait for the interval, sent after %v", elapsed)
+	}
+
+	select {
+	case msg := <-sent:
+		t.Errorf("Expected nothing more, got %q", msg)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.groups) != 0 {
+		t.Errorf("Expected idle groups to be dropped, got %d", len(g.groups))
+	}
+}
@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const defaultHPAMaxReplicasDuration = 15 * time.Minute
+
+func init() {
+	Register("hpas", func(cfg config.AppConfig) Checker {
+		threshold := cfg.Checker.HPAs.MaxReplicasDuration
+		if threshold <= 0 {
+			threshold = defaultHPAMaxReplicasDuration
+		}
+		return &hpaChecker{maxReplicasDuration: threshold}
+	})
+}
+
+type hpaChecker struct {
+	maxReplicasDuration time.Duration
+	atMax               graceTracker
+}
+
+func (c *hpaChecker) Name() string     { return "hpas" }
+func (c *hpaChecker) Resource() string { return types.ResourceTypeHPA }
+
+func (c *hpaChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	return src.Factory().Autoscaling().V2().HorizontalPodAutoscalers().Informer(), nil
+}
+
+func (c *hpaChecker) Evaluate(obj interface{}) []types.Alert {
+	hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s/%s", hpa.Namespace, hpa.Name)
+	var alerts []types.Alert
+
+	for _, cond := range hpa.Status.Conditions {
+		// metrics unavailable
+		if cond.Type == autoscalingv2.ScalingActive && cond.Status == corev1.ConditionFalse {
+			alerts = append(alerts, types.Alert{
+				Level:    types.AlertLevelError,
+				Resource: types.ResourceTypeHPA,
+				Name:     name,
+				Message:  fmt.Sprintf("Scaling not active: %s: %s", cond.Reason, cond.Message),
+				Labels:   map[string]string{types.LabelCheck: string(cond.Type)},
+			})
+		}
+
+		// cannot scale the target
+		if cond.Type == autoscalingv2.AbleToScale && cond.Status == corev1.ConditionFalse {
+			alerts = append(alerts, types.Alert{
+				Level:    types.AlertLevelError,
+				Resource: types.ResourceTypeHPA,
+				Name:     name,
+				Message:  fmt.Sprintf("Unable to scale: %s: %s", cond.Reason, cond.Message),
+				Labels:   map[string]string{types.LabelCheck: string(cond.Type)},
+			})
+		}
+	}
+
+	// saturated at the ceiling
+	if hpa.Status.CurrentReplicas < hpa.Spec.MaxReplicas {
+		c.atMax.clear(name)
+		return alerts
+	}
+
+	if atMax := c.atMax.unhealthyFor(name); atMax >= c.maxReplicasDuration {
+		alerts = append(alerts, types.Alert{
+			Level:    types.AlertLevelWarning,
+			Resource: types.ResourceTypeHPA,
+			Name:     name,
+			Message: fmt.Sprintf("At max replicas (%d) for %s",
+				hpa.Spec.MaxReplicas, atMax.Round(time.Second)),
+		})
+	}
+
+	return alerts
+}
+
+func (c *hpaChecker) Forget(obj interface{}) {
+	if hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler); ok {
+		c.atMax.clear(fmt.Sprintf("%s/%s", hpa.Namespace, hpa.Name))
+	}
+}
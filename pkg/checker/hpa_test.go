@@ -0,0 +1,114 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHPAChecker_Evaluate(t *testing.T) {
+	c := &hpaChecker{maxReplicasDuration: 10 * time.Minute}
+
+	newHPA := func(current int32, conditions ...autoscalingv2.HorizontalPodAutoscalerCondition) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{MaxReplicas: 5},
+			Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+				CurrentReplicas: current,
+				Conditions:      conditions,
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		hpa      *autoscalingv2.HorizontalPodAutoscaler
+		atMax    time.Duration
+		expected int
+	}{
+		{
+			name:     "At max replicas past threshold",
+			hpa:      newHPA(5),
+			atMax:    time.Hour,
+			expected: 1,
+		},
+		{
+			name:     "At max replicas within threshold",
+			hpa:      newHPA(5),
+			atMax:    time.Minute,
+			expected: 0,
+		},
+		{
+			name: "Metrics unavailable",
+			hpa: newHPA(2, autoscalingv2.HorizontalPodAutoscalerCondition{
+				Type:   autoscalingv2.ScalingActive,
+				Status: corev1.ConditionFalse,
+				Reason: "FailedGetResourceMetric",
+			}),
+			expected: 1,
+		},
+		{
+			name: "Unable to scale",
+			hpa: newHPA(2, autoscalingv2.HorizontalPodAutoscalerCondition{
+				Type:   autoscalingv2.AbleToScale,
+				Status: corev1.ConditionFalse,
+				Reason: "FailedGetScale",
+			}),
+			expected: 1,
+		},
+		{
+			name: "Healthy autoscaler",
+			hpa: newHPA(2,
+				autoscalingv2.HorizontalPodAutoscalerCondition{Type: autoscalingv2.AbleToScale, Status: corev1.ConditionTrue},
+				autoscalingv2.HorizontalPodAutoscalerCondition{Type: autoscalingv2.ScalingActive, Status: corev1.ConditionTrue},
+			),
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.atMax.setSince("default/api", time.Now().Add(-tt.atMax))
+
+			alerts := c.Evaluate(tt.hpa)
+
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+			}
+		})
+	}
+}
+
+func TestHPAChecker_Evaluate_SentSeparately(t *testing.T) {
+	c := &hpaChecker{maxReplicasDuration: 10 * time.Minute}
+	notifier := &MockNotifier{}
+	hc := &HealthChecker{
+		notifier:     notifier,
+		alertHistory: make(map[string]time.Time),
+	}
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{MaxReplicas: 5},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 2,
+			Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+				{Type: autoscalingv2.ScalingActive, Status: corev1.ConditionFalse, Reason: "FailedGetResourceMetric"},
+				{Type: autoscalingv2.AbleToScale, Status: corev1.ConditionFalse, Reason: "FailedGetScale"},
+			},
+		},
+	}
+	for _, alert := range c.Evaluate(hpa) {
+		hc.sendAlert(alert)
+	}
+
+	if sent := notifier.GetAlerts(); len(sent) != 2 {
+		t.Errorf("Expected both conditions to be sent, got %v", sent)
+	}
+}
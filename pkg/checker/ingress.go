@@ -0,0 +1,198 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func init() {
+	Register("ingresses", func(cfg config.AppConfig) Checker {
+		grace := cfg.Checker.Services.GracePeriod
+		if grace <= 0 {
+			grace = defaultServiceGracePeriod
+		}
+		return &ingressChecker{grace: grace}
+	})
+}
+
+const secretLookupTimeout = 10 * time.Second
+
+type ingressChecker struct {
+	services corelisters.ServiceLister
+	slices   discoverylisters.EndpointSliceLister
+	secrets  corelisters.SecretLister
+	client   kubernetes.Interface
+
+	// how long a backend may have no ready endpoints, as for services
+	grace time.Duration
+	// by namespace/service
+	unready graceTracker
+}
+
+func (c *ingressChecker) Name() string     { return "ingresses" }
+func (c *ingressChecker) Resource() string { return types.ResourceTypeIngress }
+
+func (c *ingressChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	// backends are validated against the cached services, endpoints and
+	// TLS secrets. Other secrets referenced for TLS are fetched by name.
+	c.services = src.Factory().Core().V1().Services().Lister()
+	c.slices = src.Factory().Discovery().V1().EndpointSlices().Lister()
+	c.secrets = src.TLSSecrets().Lister()
+	c.client = src.Client()
+
+	return src.Factory().Networking().V1().Ingresses().Informer(), nil
+}
+
+func (c *ingressChecker) Evaluate(obj interface{}) []types.Alert {
+	ing, ok := obj.(*networkingv1.Ingress)
+	if !ok || c.services == nil {
+		return nil
+	}
+
+	var problems []string
+	checked := make(map[string]bool)
+
+	for _, backend := range ingressServiceBackends(ing) {
+		key := fmt.Sprintf("%s:%s:%d", backend.Name, backend.Port.Name, backend.Port.Number)
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+
+		if problem := c.checkBackend(ing.Namespace, backend); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	for _, tls := range ing.Spec.TLS {
+		// no secret name means the controller's default certificate
+		if tls.SecretName == "" || checked["secret:"+tls.SecretName] {
+			continue
+		}
+		checked["secret:"+tls.SecretName] = true
+
+		if problem := c.checkSecret(ing.Namespace, tls.SecretName); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return []types.Alert{{
+		Level:    types.AlertLevelError,
+		Resource: types.ResourceTypeIngress,
+		Name:     fmt.Sprintf("%s/%s", ing.Namespace, ing.Name),
+		Message:  fmt.Sprintf("Broken backends: %s", strings.Join(problems, ", ")),
+	}}
+}
+
+// checkBackend describes what is wrong with a backend, or returns ""
+func (c *ingressChecker) checkBackend(namespace string, backend *networkingv1.IngressServiceBackend) string {
+	key := fmt.Sprintf("%s/%s", namespace, backend.Name)
+	svc, err := c.services.Services(namespace).Get(backend.Name)
+	if apierrors.IsNotFound(err) {
+		c.unready.clear(key)
+		return fmt.Sprintf("service %s not found", backend.Name)
+	}
+	if err != nil {
+		return ""
+	}
+
+	if !servicePortExists(svc, backend.Port) {
+		port := backend.Port.Name
+		if port == "" {
+			port = fmt.Sprintf("%d", backend.Port.Number)
+		}
+		return fmt.Sprintf("service %s has no port %s", backend.Name, port)
+	}
+
+	if len(svc.Spec.Selector) > 0 {
+		ready, err := readyEndpoints(c.slices, namespace, svc.Name)
+		if err != nil {
+			return ""
+		}
+		if ready > 0 {
+			c.unready.clear(key)
+			return ""
+		}
+		if down := c.unready.unhealthyFor(key); down >= c.grace {
+			return fmt.Sprintf("service %s has no ready endpoints for %s", backend.Name, down.Round(time.Second))
+		}
+	}
+
+	return ""
+}
+
+// checkSecret describes what is wrong with a TLS secret, or returns ""
+func (c *ingressChecker) checkSecret(namespace, name string) string {
+	secret, err := c.secrets.Secrets(namespace).Get(name)
+	if apierrors.IsNotFound(err) && c.client != nil {
+		// any secret type can hold a certificate, those are not cached
+		ctx, cancel := context.WithTimeout(context.Background(), secretLookupTimeout)
+		defer cancel()
+		secret, err = c.client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
+	if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("TLS secret %s not found", name)
+	}
+	// the API server makes sure kubernetes.io/tls secrets hold both
+	if err != nil || secret.Type == corev1.SecretTypeTLS {
+		return ""
+	}
+
+	_, hasCert := secret.Data[corev1.TLSCertKey]
+	_, hasKey := secret.Data[corev1.TLSPrivateKeyKey]
+	if !hasCert || !hasKey {
+		return fmt.Sprintf("TLS secret %s has no %s and %s", name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+	}
+	return ""
+}
+
+func ingressServiceBackends(ing *networkingv1.Ingress) []*networkingv1.IngressServiceBackend {
+	var backends []*networkingv1.IngressServiceBackend
+
+	if ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Service != nil {
+		backends = append(backends, ing.Spec.DefaultBackend.Service)
+	}
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service != nil {
+				backends = append(backends, path.Backend.Service)
+			}
+		}
+	}
+
+	return backends
+}
+
+func servicePortExists(svc *corev1.Service, port networkingv1.ServiceBackendPort) bool {
+	for _, p := range svc.Spec.Ports {
+		if port.Name != "" && p.Name == port.Name {
+			return true
+		}
+		if port.Name == "" && p.Port == port.Number {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package checker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestIngressChecker_Evaluate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	newService := func(name string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": name},
+				Ports:    []corev1.ServicePort{{Name: "http", Port: 80}},
+			},
+		}
+	}
+	newIngress := func(service string, port networkingv1.ServiceBackendPort, secret string) *networkingv1.Ingress {
+		ing := &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					{
+						Host: "web.example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									{
+										Path: "/",
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{Name: service, Port: port},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		if secret != "" {
+			ing.Spec.TLS = []networkingv1.IngressTLS{{SecretName: secret}}
+		}
+		return ing
+	}
+
+	client := fake.NewSimpleClientset(
+		newService("web"),
+		newService("idle"),
+		newEndpointSlice("web", true),
+		newTLSSecret("web-tls", newTestCertPEM(t, "web.example.com", time.Now().Add(90*24*time.Hour))),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "opaque-tls", Namespace: "default"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "password", Namespace: "default"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+		},
+	)
+	c := &ingressChecker{}
+	startChecker(ctx, t, c, client)
+
+	// the fake clientset ignores the type field selector of the TLS secrets
+	tlsSecrets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := tlsSecrets.Add(newTLSSecret("web-tls", nil)); err != nil {
+		t.Fatalf("Failed to add secret: %v", err)
+	}
+	c.secrets = corelisters.NewSecretLister(tlsSecrets)
+
+	tests := []struct {
+		name     string
+		ingress  *networkingv1.Ingress
+		contains string
+	}{
+		{
+			name:     "Healthy ingress",
+			ingress:  newIngress("web", networkingv1.ServiceBackendPort{Name: "http"}, "web-tls"),
+			contains: "",
+		},
+		{
+			name:     "Missing service",
+			ingress:  newIngress("gone", networkingv1.ServiceBackendPort{Number: 80}, ""),
+			contains: "service gone not found",
+		},
+		{
+			name:     "Missing port",
+			ingress:  newIngress("web", networkingv1.ServiceBackendPort{Number: 8080}, ""),
+			contains: "service web has no port 8080",
+		},
+		{
+			name:     "Opaque secret holding a certificate",
+			ingress:  newIngress("web", networkingv1.ServiceBackendPort{Name: "http"}, "opaque-tls"),
+			contains: "",
+		},
+		{
+			name:     "Secret without a certificate",
+			ingress:  newIngress("web", networkingv1.ServiceBackendPort{Name: "http"}, "password"),
+			contains: "TLS secret password has no tls.crt and tls.key",
+		},
+		{
+			name:     "Missing TLS secret",
+			ingress:  newIngress("web", networkingv1.ServiceBackendPort{Number: 80}, "other-tls"),
+			contains: "TLS secret other-tls not found",
+		},
+		{
+			name:     "Backend without ready endpoints",
+			ingress:  newIngress("idle", networkingv1.ServiceBackendPort{Name: "http"}, ""),
+			contains: "service idle has no ready endpoints",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := c.Evaluate(tt.ingress)
+			if tt.contains == "" {
+				if len(alerts) != 0 {
+					t.Errorf("Expected no alerts, got %+v", alerts)
+				}
+				return
+			}
+			if len(alerts) != 1 || !strings.Contains(alerts[0].Message, tt.contains) {
+				t.Errorf("Expected one alert containing %q, got %+v", tt.contains, alerts)
+			}
+		})
+	}
+
+	// backends get the grace period of services
+	c.grace = time.Minute
+	c.unready.clear("default/idle")
+	idle := newIngress("idle", networkingv1.ServiceBackendPort{Name: "http"}, "")
+	if alerts := c.Evaluate(idle); len(alerts) != 0 {
+		t.Errorf("Expected no alert within the grace period, got %+v", alerts)
+	}
+	c.unready.setSince("default/idle", time.Now().Add(-2*time.Minute))
+	if alerts := c.Evaluate(idle); len(alerts) != 1 {
+		t.Errorf("Expected an alert after the grace period, got %+v", alerts)
+	}
+}
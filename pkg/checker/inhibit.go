@@ -0,0 +1,101 @@
+package checker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/matcher"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// an alert counts as firing while its check keeps reporting it. Informers
+// resync every 30s, so anything not seen for longer has resolved.
+const activeAlertTimeout = 90 * time.Second
+
+type inhibitRule struct {
+	source []matcher.Matcher
+	target []matcher.Matcher
+	equal  []string
+}
+
+type activeAlert struct {
+	alert    types.Alert
+	lastSeen time.Time
+}
+
+func compileInhibitRules(rules []config.InhibitRule) ([]inhibitRule, error) {
+	compiled := make([]inhibitRule, 0, len(rules))
+	for i, rule := range rules {
+		source, err := matcher.ParseAll(rule.SourceMatchers)
+		if err != nil {
+			return nil, fmt.Errorf("inhibit rule %d: source: %w", i+1, err)
+		}
+		target, err := matcher.ParseAll(rule.TargetMatchers)
+		if err != nil {
+			return nil, fmt.Errorf("inhibit rule %d: target: %w", i+1, err)
+		}
+		if len(source) == 0 || len(target) == 0 {
+			return nil, fmt.Errorf("inhibit rule %d: source and target matchers are required", i+1)
+		}
+
+		compiled = append(compiled, inhibitRule{source: source, target: target, equal: rule.Equal})
+	}
+	return compiled, nil
+}
+
+// inhibited reports whether a firing alert inhibits alert. The caller
+// holds hc.mu.
+func (hc *HealthChecker) inhibited(key string, alert types.Alert, now time.Time) bool {
+	for _, rule := range hc.inhibitRules {
+		if !matcher.MatchesAll(rule.target, alert) {
+			continue
+		}
+
+		for activeKey, active := range hc.activeAlerts {
+			if activeKey == key || now.Sub(active.lastSeen) > activeAlertTimeout {
+				continue
+			}
+			if !matcher.MatchesAll(rule.source, active.alert) {
+				continue
+			}
+
+			equal := true
+			for _, label := range rule.equal {
+				if active.alert.Label(label) != alert.Label(label) {
+					equal = false
+					break
+				}
+			}
+			if equal {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// objectLabels returns the labels every alert about obj carries
+func objectLabels(obj interface{}) map[string]string {
+	labels := make(map[string]string)
+
+	if accessor, err := meta.Accessor(obj); err == nil && accessor.GetNamespace() != "" {
+		labels[types.LabelNamespace] = accessor.GetNamespace()
+	}
+	if node, ok := obj.(*corev1.Node); ok {
+		labels[types.LabelNode] = node.Name
+	}
+
+	return labels
+}
+
+// descriptionAnnotations returns the annotations of an alert described by
+// description, nil when there is nothing to describe
+func descriptionAnnotations(description string) map[string]string {
+	if description == "" {
+		return nil
+	}
+	return map[string]string{types.AnnotationDescription: description}
+}
@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+func TestHealthChecker_sendAlert_Inhibited(t *testing.T) {
+	rules, err := compileInhibitRules([]config.InhibitRule{{
+		SourceMatchers: []string{`resource="node"`, `level="critical"`},
+		TargetMatchers: []string{`resource=~"pod|deployment"`},
+		Equal:          []string{"node"},
+	}})
+	if err != nil {
+		t.Fatalf("compileInhibitRules failed: %v", err)
+	}
+
+	notifier := &MockNotifier{}
+	hc := &HealthChecker{
+		notifier:     notifier,
+		alertHistory: make(map[string]time.Time),
+		inhibitRules: rules,
+	}
+
+	podOn := func(name, node string) types.Alert {
+		return types.Alert{
+			Level:    types.AlertLevelError,
+			Resource: types.ResourceTypePod,
+			Name:     "default/" + name,
+			Message:  "Container is in CrashLoopBackOff",
+			Labels:   map[string]string{types.LabelNode: node},
+		}
+	}
+
+	hc.sendAlert(types.Alert{
+		Level:    types.AlertLevelCritical,
+		Resource: types.ResourceTypeNode,
+		Name:     "rpi-1",
+		Message:  "Node is not ready: NodeStatusUnknown",
+		Labels:   map[string]string{types.LabelNode: "rpi-1"},
+	})
+	hc.sendAlert(podOn("api-1", "rpi-1"))
+	hc.sendAlert(podOn("api-2", "rpi-2"))
+
+	if len(notifier.GetAlerts()) != 2 {
+		t.Fatalf("Expected the node alert and the pod on rpi-2, got %+v", notifier.GetAlerts())
+	}
+
+	// once the node alert stops firing the pod alert goes out
+	for key, active := range hc.activeAlerts {
+		active.lastSeen = time.Now().Add(-2 * activeAlertTimeout)
+		hc.activeAlerts[key] = active
+	}
+	hc.sendAlert(podOn("api-1", "rpi-1"))
+	if len(notifier.GetAlerts()) != 3 {
+		t.Errorf("Expected the pod alert after the node resolved, got %d alerts", len(notifier.GetAlerts()))
+	}
+}
+
+func TestCompileInhibitRules_Invalid(t *testing.T) {
+	_, err := compileInhibitRules([]config.InhibitRule{{SourceMatchers: []string{`resource="node"`}}})
+	if err == nil {
+		t.Error("Expected a rule without target matchers to fail")
+	}
+}
@@ -0,0 +1,189 @@
+package checker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+const defaultCronJobMissedSchedules = 1
+
+func init() {
+	Register("jobs", func(cfg config.AppConfig) Checker {
+		return &jobChecker{maxDuration: cfg.Checker.Jobs.MaxDuration}
+	})
+	Register("cronjobs", func(cfg config.AppConfig) Checker {
+		missed := cfg.Checker.CronJobs.MissedSchedules
+		if missed <= 0 {
+			missed = defaultCronJobMissedSchedules
+		}
+		return &cronJobChecker{missedSchedules: missed}
+	})
+}
+
+type jobChecker struct {
+	// 0 disables the long running job check
+	maxDuration time.Duration
+	// failures before startup are history, not news
+	startedAt time.Time
+
+	// failed jobs already reported, they stay around for
+	// failedJobsHistoryLimit and would otherwise be reported on every resync
+	mu     sync.Mutex
+	failed map[k8stypes.UID]bool
+}
+
+func (c *jobChecker) Name() string     { return "jobs" }
+func (c *jobChecker) Resource() string { return types.ResourceTypeJob }
+
+func (c *jobChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	c.startedAt = time.Now()
+
+	return src.Factory().Batch().V1().Jobs().Informer(), nil
+}
+
+func (c *jobChecker) Evaluate(obj interface{}) []types.Alert {
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s/%s", job.Namespace, job.Name)
+
+	for _, cond := range job.Status.Conditions {
+		// failed, e.g. BackoffLimitExceeded or DeadlineExceeded
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			if !c.startedAt.IsZero() && cond.LastTransitionTime.Time.Before(c.startedAt) {
+				return nil
+			}
+			if !c.firstFailure(job.UID) {
+				return nil
+			}
+			return []types.Alert{{
+				Level:    types.AlertLevelError,
+				Resource: types.ResourceTypeJob,
+				Name:     name,
+				Message:  fmt.Sprintf("Job failed: %s: %s", cond.Reason, cond.Message),
+			}}
+		}
+
+		if cond.Type == batchv1.JobComplete && cond.Status == corev1.ConditionTrue {
+			return nil
+		}
+	}
+
+	// running for too long
+	if c.maxDuration > 0 && job.Status.StartTime != nil && job.Status.CompletionTime == nil {
+		running := time.Since(job.Status.StartTime.Time)
+		if running > c.maxDuration {
+			return []types.Alert{{
+				Level:    types.AlertLevelWarning,
+				Resource: types.ResourceTypeJob,
+				Name:     name,
+				Message: fmt.Sprintf("Job running for %s, longer than %s",
+					running.Round(time.Second), c.maxDuration),
+			}}
+		}
+	}
+
+	return nil
+}
+
+// firstFailure records that the job with uid failed and reports whether that
+// is news
+func (c *jobChecker) firstFailure(uid k8stypes.UID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failed == nil {
+		c.failed = make(map[k8stypes.UID]bool)
+	}
+	if c.failed[uid] {
+		return false
+	}
+	c.failed[uid] = true
+	return true
+}
+
+func (c *jobChecker) Forget(obj interface{}) {
+	if job, ok := obj.(*batchv1.Job); ok {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		delete(c.failed, job.UID)
+	}
+}
+
+type cronJobChecker struct {
+	// schedules missed since the last success before alerting
+	missedSchedules int
+}
+
+func (c *cronJobChecker) Name() string     { return "cronjobs" }
+func (c *cronJobChecker) Resource() string { return types.ResourceTypeCronJob }
+
+func (c *cronJobChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	return src.Factory().Batch().V1().CronJobs().Informer(), nil
+}
+
+func (c *cronJobChecker) Evaluate(obj interface{}) []types.Alert {
+	cj, ok := obj.(*batchv1.CronJob)
+	if !ok || (cj.Spec.Suspend != nil && *cj.Spec.Suspend) {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s/%s", cj.Namespace, cj.Name)
+
+	spec := cj.Spec.Schedule
+	if cj.Spec.TimeZone != nil {
+		spec = fmt.Sprintf("CRON_TZ=%s %s", *cj.Spec.TimeZone, spec)
+	}
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return []types.Alert{{
+			Level:    types.AlertLevelWarning,
+			Resource: types.ResourceTypeCronJob,
+			Name:     name,
+			Message:  fmt.Sprintf("Unparseable schedule %q: %v", cj.Spec.Schedule, err),
+		}}
+	}
+
+	missed := c.missedSchedules
+
+	lastSuccess := cj.CreationTimestamp.Time
+	if cj.Status.LastSuccessfulTime != nil {
+		lastSuccess = cj.Status.LastSuccessfulTime.Time
+	}
+
+	deadline := lastSuccess
+	for i := 0; i < missed; i++ {
+		deadline = schedule.Next(deadline)
+	}
+
+	// the last missed run may still be in progress, one that hangs is left
+	// to the max duration check of the jobs
+	if time.Now().After(deadline) && len(cj.Status.Active) == 0 {
+		msg := fmt.Sprintf("No successful run for %s, missed %d or more schedules",
+			time.Since(lastSuccess).Round(time.Minute), missed)
+		if cj.Status.LastSuccessfulTime == nil {
+			msg = fmt.Sprintf("No successful run since creation %s ago, missed %d or more schedules",
+				time.Since(lastSuccess).Round(time.Minute), missed)
+		}
+		return []types.Alert{{
+			Level:    types.AlertLevelError,
+			Resource: types.ResourceTypeCronJob,
+			Name:     name,
+			Message:  msg,
+		}}
+	}
+
+	return nil
+}
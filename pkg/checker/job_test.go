@@ -0,0 +1,229 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+func TestJobChecker_Evaluate(t *testing.T) {
+	c := &jobChecker{maxDuration: time.Hour}
+
+	started := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	recent := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+
+	tests := []struct {
+		name     string
+		job      *batchv1.Job
+		expected int
+	}{
+		{
+			name: "Job exceeded backoff limit",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "default"},
+				Status: batchv1.JobStatus{
+					StartTime: &started,
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:   batchv1.JobFailed,
+							Status: corev1.ConditionTrue,
+							Reason: "BackoffLimitExceeded",
+						},
+					},
+				},
+			},
+			expected: 1,
+		},
+		{
+			name: "Job running longer than max duration",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup-2", Namespace: "default"},
+				Status:     batchv1.JobStatus{StartTime: &started},
+			},
+			expected: 1,
+		},
+		{
+			name: "Job running within max duration",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup-3", Namespace: "default"},
+				Status:     batchv1.JobStatus{StartTime: &recent},
+			},
+			expected: 0,
+		},
+		{
+			name: "Completed job",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup-4", Namespace: "default"},
+				Status: batchv1.JobStatus{
+					StartTime: &started,
+					Conditions: []batchv1.JobCondition{
+						{
+							Type:   batchv1.JobComplete,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := c.Evaluate(tt.job)
+
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+			}
+		})
+	}
+}
+
+func TestJobChecker_Evaluate_FailedOnce(t *testing.T) {
+	c := &jobChecker{startedAt: time.Now().Add(-time.Minute)}
+
+	newJob := func(uid string, failedAt time.Time) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: uid, Namespace: "default", UID: k8stypes.UID(uid)},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{
+					Type:               batchv1.JobFailed,
+					Status:             corev1.ConditionTrue,
+					Reason:             "BackoffLimitExceeded",
+					LastTransitionTime: metav1.NewTime(failedAt),
+				}},
+			},
+		}
+	}
+
+	job := newJob("backup-1", time.Now())
+	if alerts := c.Evaluate(job); len(alerts) != 1 {
+		t.Fatalf("Expected the failure to be reported, got %+v", alerts)
+	}
+	if alerts := c.Evaluate(job); len(alerts) != 0 {
+		t.Errorf("Expected the failure to be reported once, got %+v", alerts)
+	}
+	if alerts := c.Evaluate(newJob("backup-0", time.Now().Add(-time.Hour))); len(alerts) != 0 {
+		t.Errorf("Expected a failure before startup to be skipped, got %+v", alerts)
+	}
+
+	c.Forget(job)
+	if alerts := c.Evaluate(job); len(alerts) != 1 {
+		t.Errorf("Expected a forgotten job to be reported anew, got %+v", alerts)
+	}
+}
+
+func TestCronJobChecker_Evaluate_MissedSchedules(t *testing.T) {
+	utc := "UTC"
+	// two hourly runs missed since the success
+	lastSuccess := metav1.NewTime(time.Now().UTC().Truncate(time.Hour).Add(-2*time.Hour + 10*time.Minute))
+	newCronJob := func(active int) *batchv1.CronJob {
+		cj := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default"},
+			Spec:       batchv1.CronJobSpec{Schedule: "0 * * * *", TimeZone: &utc},
+			Status:     batchv1.CronJobStatus{LastSuccessfulTime: &lastSuccess},
+		}
+		for i := 0; i < active; i++ {
+			cj.Status.Active = append(cj.Status.Active, corev1.ObjectReference{Name: "report-run"})
+		}
+		return cj
+	}
+
+	tests := []struct {
+		name     string
+		missed   int
+		active   int
+		expected int
+	}{
+		{name: "Exactly the missed schedules", missed: 2, expected: 1},
+		{name: "Fewer than the missed schedules", missed: 3, expected: 0},
+		{name: "Last run still in progress", missed: 2, active: 1, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &cronJobChecker{missedSchedules: tt.missed}
+			if alerts := c.Evaluate(newCronJob(tt.active)); len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %+v", tt.expected, alerts)
+			}
+		})
+	}
+}
+
+func TestCronJobChecker_Evaluate(t *testing.T) {
+	c := &cronJobChecker{missedSchedules: 1}
+
+	created := metav1.NewTime(time.Now().Add(-30 * 24 * time.Hour))
+	threeDaysAgo := metav1.NewTime(time.Now().Add(-3 * 24 * time.Hour))
+	lastHour := metav1.NewTime(time.Now().Add(-time.Hour))
+	suspend := true
+
+	tests := []struct {
+		name     string
+		cronJob  *batchv1.CronJob
+		expected int
+	}{
+		{
+			name: "Nightly backup missed runs",
+			cronJob: &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default", CreationTimestamp: created},
+				Spec:       batchv1.CronJobSpec{Schedule: "0 2 * * *"},
+				Status:     batchv1.CronJobStatus{LastSuccessfulTime: &threeDaysAgo},
+			},
+			expected: 1,
+		},
+		{
+			name: "Nightly backup succeeded recently",
+			cronJob: &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default", CreationTimestamp: created},
+				Spec:       batchv1.CronJobSpec{Schedule: "0 2 * * *"},
+				Status:     batchv1.CronJobStatus{LastSuccessfulTime: &lastHour},
+			},
+			expected: 0,
+		},
+		{
+			name: "Never succeeded since creation",
+			cronJob: &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default", CreationTimestamp: created},
+				Spec:       batchv1.CronJobSpec{Schedule: "@hourly"},
+			},
+			expected: 1,
+		},
+		{
+			name: "Suspended cronjob",
+			cronJob: &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "cleanup", Namespace: "default", CreationTimestamp: created},
+				Spec:       batchv1.CronJobSpec{Schedule: "@hourly", Suspend: &suspend},
+			},
+			expected: 0,
+		},
+		{
+			name: "Invalid schedule",
+			cronJob: &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "default", CreationTimestamp: created},
+				Spec:       batchv1.CronJobSpec{Schedule: "not a schedule"},
+			},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := c.Evaluate(tt.cronJob)
+
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+			}
+		})
+	}
+}
@@ -3,16 +3,23 @@ package checker
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/message"
 	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 )
 
 type HealthChecker struct {
@@ -21,6 +28,45 @@ type HealthChecker struct {
 	config       config.AppConfig
 	notifier     Notifier
 	alertHistory map[string]time.Time
+	startedAt    time.Time
+
+	// only kubernetes.io/tls secrets, created when certificate checks are enabled
+	secretFactory informers.SharedInformerFactory
+
+	// custom resources from config.Checker.CustomResources
+	dynamicClient  dynamic.Interface
+	dynamicFactory dynamicinformer.DynamicSharedInformerFactory
+
+	// compiled config.Checker.CELRules by resource
+	celRules map[string][]celRule
+
+	// objects selected by config.Checker.Scope
+	scope *scope
+
+	// per object severity and route annotations
+	overrides *overrides
+	// notifiers by route name, see UseRoute
+	routes map[string]Notifier
+	// message templates by route name, "" for the default notifier
+	templates map[string]*message.Templates
+
+	// compiled config.InhibitRules and the alerts they are checked against
+	inhibitRules []inhibitRule
+	activeAlerts map[string]activeAlert
+
+	// silences consulted before notifying, see UseSilences
+	silences Silencer
+	// compiled config.MaintenanceWindows
+	maintenanceWindows []*maintenanceWindow
+	// batches notifications when config.Grouping.By is set
+	grouper *grouper
+
+	// alerts currently firing by fingerprint, and the compiled
+	// config.Escalations run against them
+	firing             map[string]*firingAlert
+	escalationPolicies []escalationPolicy
+
+	mu sync.Mutex
 }
 
 const (
@@ -32,6 +78,11 @@ type Notifier interface {
 	Notifiy(message string) error
 }
 
+// Silencer mutes alerts, implemented by silence.Store
+type Silencer interface {
+	Silenced(alert types.Alert) bool
+}
+
 func NewHealthChecker(
 	ctx context.Context,
 	client kubernetes.Interface,
@@ -50,164 +101,350 @@ func NewHealthChecker(
 }
 
 func (hc *HealthChecker) Start(ctx context.Context) error {
-	if hc.config.Checker.CheckPods {
-		_, err := hc.factory.Core().V1().Pods().Informer().AddEventHandler(
-			cache.ResourceEventHandlerFuncs{
-				UpdateFunc: func(old, new interface{}) {
-					hc.checkPod(new.(*corev1.Pod))
-				},
-			},
-		)
-		if err != nil {
-			return fmt.Errorf("failed to add pod event handler: %w", err)
+	hc.startedAt = time.Now()
+
+	celRules, err := compileCELRules(hc.config.Checker.CELRules)
+	if err != nil {
+		return err
+	}
+	hc.celRules = celRules
+
+	scope, err := newScope(hc.config)
+	if err != nil {
+		return err
+	}
+	hc.scope = scope
+	hc.overrides = newOverrides(hc.factory)
+
+	inhibitRules, err := compileInhibitRules(hc.config.InhibitRules)
+	if err != nil {
+		return err
+	}
+	hc.inhibitRules = inhibitRules
+
+	windows, err := compileMaintenanceWindows(hc.config.MaintenanceWindows)
+	if err != nil {
+		return err
+	}
+	hc.maintenanceWindows = windows
+
+	policies, err := compileEscalationPolicies(hc.config.Escalations)
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		for _, step := range policy.steps {
+			if _, ok := hc.routes[step.receiver]; !ok {
+				return fmt.Errorf("escalation %s: unknown receiver %q", policy.name, step.receiver)
+			}
 		}
 	}
+	hc.escalationPolicies = policies
 
-	if hc.config.Checker.CheckNodes {
-		_, err := hc.factory.Core().V1().Nodes().Informer().AddEventHandler(
-			cache.ResourceEventHandlerFuncs{
-				UpdateFunc: func(old, new interface{}) {
-					hc.checkNode(new.(*corev1.Node))
-				},
-			})
-		if err != nil {
-			return fmt.Errorf("failed to add node event handler: %w", err)
+	if len(hc.config.Grouping.By) > 0 {
+		hc.grouper = newGrouper(hc.config.Grouping.By, hc.config.Grouping.GroupWait,
+			hc.config.Grouping.GroupInterval, hc.format, hc.deliver)
+	}
+
+	checkers, err := enabledCheckers(hc.config)
+	if err != nil {
+		return err
+	}
+	if err := hc.checkCustomResources(); err != nil {
+		return err
+	}
+
+	// a rule for a resource no checker watches would never be evaluated
+	resources := make(map[string]bool, len(checkers))
+	for _, c := range checkers {
+		resources[c.Resource()] = true
+	}
+	for _, rule := range hc.config.Checker.CELRules {
+		if !resources[rule.Resource] {
+			return fmt.Errorf("CEL rule %q: no enabled checker for resource %q", rule.Name, rule.Resource)
 		}
 	}
 
-	if hc.config.Checker.CheckDeployments {
-		_, err := hc.factory.Apps().V1().Deployments().Informer().AddEventHandler(
-			cache.ResourceEventHandlerFuncs{
-				UpdateFunc: func(old, new interface{}) {
-					hc.checkDeployment(new.(*appsv1.Deployment))
-				},
-			})
+	for _, c := range checkers {
+		informer, err := c.Informer(hc)
 		if err != nil {
-			return fmt.Errorf("failed to add deployment event handler: %w", err)
+			return fmt.Errorf("failed to set up %s checker: %w", c.Name(), err)
+		}
+
+		handler := cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				hc.evaluate(c, obj)
+			},
+			UpdateFunc: func(old, new interface{}) {
+				hc.evaluate(c, new)
+			},
+		}
+		if f, ok := c.(Forgetter); ok {
+			handler.DeleteFunc = func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				f.Forget(obj)
+			}
+		}
+
+		if _, err := informer.AddEventHandler(handler); err != nil {
+			return fmt.Errorf("failed to add %s event handler: %w", c.Name(), err)
 		}
 	}
 
 	hc.factory.Start(ctx.Done())
 	hc.factory.WaitForCacheSync(ctx.Done())
 
+	if hc.secretFactory != nil {
+		hc.secretFactory.Start(ctx.Done())
+		hc.secretFactory.WaitForCacheSync(ctx.Done())
+	}
+
+	if hc.dynamicFactory != nil {
+		hc.dynamicFactory.Start(ctx.Done())
+		hc.dynamicFactory.WaitForCacheSync(ctx.Done())
+	}
+
+	if len(hc.maintenanceWindows) > 0 {
+		go hc.watchMaintenanceWindows(ctx)
+	}
+	go hc.watchFiring(ctx)
+
 	fmt.Println("Health checker succesfully enabled")
 	return nil
 }
 
-func (hc *HealthChecker) checkPod(pod *corev1.Pod) {
-	//pod failed
-	if pod.Status.Phase == corev1.PodFailed {
-		hc.sendAlert(types.Alert{
-			Level:    types.AlertLevelError,
-			Resource: types.ResourceTypePod,
-			Name:     fmt.Sprintf("%s/%s", pod.Namespace, pod.Name),
-			Message:  fmt.Sprintf("Pod failed: %s", pod.Status.Reason),
-		})
+// evaluate runs a checker and the CEL rules for its resource against obj
+func (hc *HealthChecker) evaluate(c Checker, obj interface{}) {
+	if !hc.InScope(obj) {
+		// an object can leave the scope, e.g. by being annotated
+		if f, ok := c.(Forgetter); ok {
+			f.Forget(obj)
+		}
+		return
 	}
 
-	for _, cs := range pod.Status.ContainerStatuses {
-		// crashloopfallback
-		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
-			hc.sendAlert(types.Alert{
-				Level:    types.AlertLevelError,
-				Resource: types.ResourceTypePod,
-				Name:     fmt.Sprintf("%s/%s", pod.Namespace, pod.Name),
-				Message:  "Container is in CrashLoopBackOff",
-			})
-		}
+	for _, alert := range c.Evaluate(obj) {
+		hc.sendAlert(hc.prepare(alert, obj))
+	}
+	hc.checkCELRules(c.Resource(), obj)
+}
 
-		// restart
-		if cs.RestartCount > 5 {
-			hc.sendAlert(types.Alert{
-				Level:    types.AlertLevelWarning,
-				Resource: types.ResourceTypePod,
-				Name:     fmt.Sprintf("%s/%s", pod.Namespace, pod.Name),
-				Message:  fmt.Sprintf("High restart count: %d", cs.RestartCount),
-			})
-		}
+// UseSilences mutes the alerts silenced by s
+func (hc *HealthChecker) UseSilences(s Silencer) {
+	hc.silences = s
+}
 
-		// pod waiting or image pull back of ffff
-		if cs.State.Waiting != nil &&
-			(cs.State.Waiting.Reason == "ImagePullBackOff" || cs.State.Waiting.Reason == "ErrImagePull") {
-			hc.sendAlert(types.Alert{
-				Level:    types.AlertLevelError,
-				Resource: types.ResourceTypePod,
-				Name:     fmt.Sprintf("%s/%s", pod.Namespace, pod.Name),
-				Message:  fmt.Sprintf("Image pull failed: %s", cs.State.Waiting.Reason),
-			})
-		}
+// UseRoute sends alerts routed to route, through the route annotation, to
+// notifier instead of the default one
+func (hc *HealthChecker) UseRoute(route string, notifier Notifier) {
+	if hc.routes == nil {
+		hc.routes = make(map[string]Notifier)
+	}
+	hc.routes[route] = notifier
+}
+
+// UseTemplates formats the notifications sent through route, "" for the
+// default notifier, with t
+func (hc *HealthChecker) UseTemplates(route string, t *message.Templates) {
+	if hc.templates == nil {
+		hc.templates = make(map[string]*message.Templates)
 	}
+	hc.templates[route] = t
 }
 
-func (hc *HealthChecker) checkNode(node *corev1.Node) {
-	for _, cond := range node.Status.Conditions {
-		// not ready
-		if cond.Type == corev1.NodeReady && cond.Status != corev1.ConditionTrue {
-			hc.sendAlert(types.Alert{
-				Level:    types.AlertLevelCritical,
-				Resource: types.ResourceTypeNode,
-				Name:     node.Name,
-				Message:  fmt.Sprintf("Node is not ready: %s", cond.Reason),
-			})
+// prepare adds the labels of obj, including the workload owning it, to an
+// alert about it and applies its overrides
+func (hc *HealthChecker) prepare(alert types.Alert, obj interface{}) types.Alert {
+	labels := objectLabels(obj)
+	if hc.overrides != nil {
+		if accessor, err := meta.Accessor(obj); err == nil {
+			if w, ok := hc.overrides.owners.workloadOf(accessor); ok {
+				labels[types.LabelOwner] = w.String()
+			}
 		}
+	}
+	for name, value := range alert.Labels {
+		labels[name] = value
+	}
+	alert.Labels = labels
+
+	return hc.applyOverrides(alert, obj)
+}
+
+// applyOverrides sets the severity and route annotated on obj
+func (hc *HealthChecker) applyOverrides(alert types.Alert, obj interface{}) types.Alert {
+	if hc.overrides == nil {
+		return alert
+	}
 
-		// mem pressure
-		if cond.Type == corev1.NodeMemoryPressure && cond.Status == corev1.ConditionTrue {
-			hc.sendAlert(types.Alert{
-				Level:    types.AlertLevelWarning,
-				Resource: types.ResourceTypeNode,
-				Name:     node.Name,
-				Message:  "Node has memory pressure",
-			})
+	if level := hc.overrides.severity(obj); level != "" {
+		alert.Level = level
+	}
+
+	if route, source := hc.overrides.route(obj); route != "" {
+		if _, ok := hc.routes[route]; ok {
+			alert.Route = route
+		} else {
+			hc.overrides.invalid(source, RouteAnnotation, route, "no such route")
 		}
+	}
 
-		// disk pressure
-		if cond.Type == corev1.NodeDiskPressure && cond.Status == corev1.ConditionTrue {
-			hc.sendAlert(types.Alert{
-				Level:    types.AlertLevelWarning,
-				Resource: types.ResourceTypeNode,
-				Name:     node.Name,
-				Message:  "Node has disk pressure",
-			})
+	runbook := hc.overrides.runbookURL(obj)
+	description := hc.overrides.description(obj)
+	if runbook != "" || (description != "" && alert.Annotations[types.AnnotationDescription] == "") {
+		annotations := make(map[string]string, len(alert.Annotations)+2)
+		for name, value := range alert.Annotations {
+			annotations[name] = value
+		}
+		if runbook != "" {
+			annotations[types.AnnotationRunbookURL] = runbook
 		}
+		// what the checker found is more specific
+		if annotations[types.AnnotationDescription] == "" && description != "" {
+			annotations[types.AnnotationDescription] = description
+		}
+		alert.Annotations = annotations
 	}
+
+	return alert
 }
 
-func (hc *HealthChecker) checkDeployment(deploy *appsv1.Deployment) {
-	if deploy.Spec.Replicas == nil {
-		return
-	}
+// Factory returns the shared informer factory for built-in resources
+func (hc *HealthChecker) Factory() informers.SharedInformerFactory {
+	return hc.factory
+}
 
-	desired := *deploy.Spec.Replicas
-	available := deploy.Status.AvailableReplicas
+// Client returns the clientset the informers are built from
+func (hc *HealthChecker) Client() kubernetes.Interface {
+	return hc.client
+}
 
-	if available < desired {
-		hc.sendAlert(types.Alert{
-			Level:    types.AlertLevelWarning,
-			Resource: types.ResourceTypeDeployment,
-			Name:     fmt.Sprintf("%s/%s", deploy.Namespace, deploy.Name),
-			Message:  fmt.Sprintf("Replicas not ready: %d/%d available", available, desired),
-		})
+// TLSSecrets returns the informer for kubernetes.io/tls secrets, creating its
+// factory on first use so other secrets are never listed or cached
+func (hc *HealthChecker) TLSSecrets() coreinformers.SecretInformer {
+	if hc.secretFactory == nil {
+		hc.secretFactory = informers.NewSharedInformerFactoryWithOptions(hc.client, 30*time.Second,
+			informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+				opts.FieldSelector = fields.OneTermEqualSelector("type", string(corev1.SecretTypeTLS)).String()
+			}))
 	}
+	return hc.secretFactory.Core().V1().Secrets()
+}
+
+// DynamicFactory returns the informer factory for custom resources, creating
+// it on first use. It is nil when no dynamic client is set.
+func (hc *HealthChecker) DynamicFactory() dynamicinformer.DynamicSharedInformerFactory {
+	if hc.dynamicFactory == nil && hc.dynamicClient != nil {
+		hc.dynamicFactory = dynamicinformer.NewDynamicSharedInformerFactory(hc.dynamicClient, 30*time.Second)
+	}
+	return hc.dynamicFactory
+}
+
+// InScope reports whether obj is within the configured scope and not ignored
+func (hc *HealthChecker) InScope(obj interface{}) bool {
+	return hc.scope == nil || hc.scope.matches(obj)
 }
 
 func (hc *HealthChecker) sendAlert(alert types.Alert) {
-	alertKey := fmt.Sprintf("%s:%s:%s", alert.Level, alert.Resource, alert.Name)
+	if alert.Cluster == "" {
+		alert.Cluster = hc.config.Cluster
+	}
+	alertKey := alert.Fingerprint()
 	now := time.Now()
 
+	hc.mu.Lock()
+	if len(hc.inhibitRules) > 0 {
+		if hc.activeAlerts == nil {
+			hc.activeAlerts = make(map[string]activeAlert)
+		}
+		for key, active := range hc.activeAlerts {
+			if now.Sub(active.lastSeen) > activeAlertTimeout {
+				delete(hc.activeAlerts, key)
+			}
+		}
+		// inhibited alerts still fire and can inhibit others
+		hc.activeAlerts[alertKey] = activeAlert{alert: alert, lastSeen: now}
+
+		if hc.inhibited(alertKey, alert, now) {
+			hc.mu.Unlock()
+			return
+		}
+	}
+
+	// silenced alerts are not recorded so they go out once the silence ends
+	if hc.silences != nil && hc.silences.Silenced(alert) {
+		hc.mu.Unlock()
+		return
+	}
+
+	if len(hc.maintenanceWindows) > 0 {
+		var send bool
+		if alert, send = hc.maintenance(alertKey, alert, now); !send {
+			hc.mu.Unlock()
+			return
+		}
+		alertKey = alert.Fingerprint()
+	}
+
+	firing := hc.fire(alertKey, alert, now)
+	if firing.ackedBy != "" {
+		hc.mu.Unlock()
+		return
+	}
+
 	if lastAlert, exists := hc.alertHistory[alertKey]; exists {
 		if now.Sub(lastAlert) < 5*time.Minute {
+			hc.mu.Unlock()
 			return
 		}
 	}
 
 	hc.alertHistory[alertKey] = now
+	firing.notified = true
+	data := firing.data(message.StatusFiring)
+	hc.mu.Unlock()
 
-	msg := alert.FormatMessage()
+	if hc.grouper != nil {
+		hc.grouper.add(alertKey, data)
+		return
+	}
+	hc.notify(data)
+}
+
+// notify sends a notification through the notifier of its route
+func (hc *HealthChecker) notify(d *message.Data) {
+	hc.deliver(d.Route, hc.format(d.Route, d))
+}
+
+// format formats a notification with the templates of route, or the default
+// notifier's for unknown routes
+func (hc *HealthChecker) format(route string, d *message.Data) string {
+	if _, ok := hc.routes[route]; !ok {
+		route = ""
+	}
+	if t := hc.templates[route]; t != nil {
+		msg, err := t.Render(d)
+		if err == nil {
+			return msg
+		}
+		fmt.Printf("Failed to render message template: %v\n", err)
+	}
+	return message.Default(d)
+}
+
+// deliver sends a message through the notifier of route
+func (hc *HealthChecker) deliver(route, msg string) {
 	fmt.Println(msg)
 
-	if hc.notifier != nil {
-		if err := hc.notifier.Notifiy(msg); err != nil {
+	notifier := hc.notifier
+	if routed, ok := hc.routes[route]; ok {
+		notifier = routed
+	}
+
+	if notifier != nil {
+		if err := notifier.Notifiy(msg); err != nil {
 			fmt.Printf("Failed to send notification: %v\n", err)
 		}
 	}
@@ -2,11 +2,14 @@ package checker
 
 import (
 	"context"
-	"fmt"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/message"
+	"github.com/5iing/k8s-reliablity-informer/pkg/silence"
 	"github.com/5iing/k8s-reliablity-informer/pkg/types"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -14,7 +17,10 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 )
 
+// MockNotifier records notifications, which informer handlers send from
+// their own goroutines
 type MockNotifier struct {
+	mu     sync.Mutex
 	alerts []types.Alert
 }
 
@@ -25,32 +31,34 @@ func (m *MockNotifier) Notifiy(message string) error {
 		Name:     "test",
 		Message:  message,
 	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.alerts = append(m.alerts, alert)
 	return nil
 }
 
 func (m *MockNotifier) GetAlerts() []types.Alert {
-	return m.alerts
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]types.Alert{}, m.alerts...)
 }
 
 func (m *MockNotifier) ClearAlerts() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.alerts = []types.Alert{}
 }
 
 func TestNewHealthChecker(t *testing.T) {
 	ctx := context.Background()
 	client := fake.NewSimpleClientset()
-	config := config.AppConfig{
-		Checker: struct {
-			CheckPods        bool `yaml:"check_pods"`
-			CheckNodes       bool `yaml:"check_nodes"`
-			CheckDeployments bool `yaml:"check_deployments"`
-		}{
-			CheckPods:        true,
-			CheckNodes:       true,
-			CheckDeployments: true,
-		},
-	}
+	config := config.AppConfig{}
+	config.Checker.CheckPods = true
+	config.Checker.CheckNodes = true
+	config.Checker.CheckDeployments = true
 	notifier := &MockNotifier{}
 
 	hc := NewHealthChecker(ctx, client, config, notifier)
@@ -61,7 +69,7 @@ func TestNewHealthChecker(t *testing.T) {
 	if hc.client != client {
 		t.Error("Expected client to be set")
 	}
-	if hc.config != config {
+	if !reflect.DeepEqual(hc.config, config) {
 		t.Error("Expected config to be set")
 	}
 	if hc.notifier != notifier {
@@ -77,17 +85,10 @@ func TestHealthChecker_Start(t *testing.T) {
 	defer cancel()
 
 	client := fake.NewSimpleClientset()
-	config := config.AppConfig{
-		Checker: struct {
-			CheckPods        bool `yaml:"check_pods"`
-			CheckNodes       bool `yaml:"check_nodes"`
-			CheckDeployments bool `yaml:"check_deployments"`
-		}{
-			CheckPods:        true,
-			CheckNodes:       true,
-			CheckDeployments: true,
-		},
-	}
+	config := config.AppConfig{}
+	config.Checker.CheckPods = true
+	config.Checker.CheckNodes = true
+	config.Checker.CheckDeployments = true
 	notifier := &MockNotifier{}
 
 	hc := NewHealthChecker(ctx, client, config, notifier)
@@ -98,12 +99,8 @@ func TestHealthChecker_Start(t *testing.T) {
 	}
 }
 
-func TestHealthChecker_checkPod(t *testing.T) {
-	notifier := &MockNotifier{}
-	hc := &HealthChecker{
-		notifier:     notifier,
-		alertHistory: make(map[string]time.Time),
-	}
+func TestPodChecker_Evaluate(t *testing.T) {
+	c := &podChecker{}
 
 	tests := []struct {
 		name     string
@@ -213,12 +210,11 @@ func TestHealthChecker_checkPod(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			notifier.ClearAlerts()
-			hc.checkPod(tt.pod)
+			alerts := c.Evaluate(tt.pod)
 
-			if len(notifier.GetAlerts()) != tt.expected {
-				t.Errorf("Expected %d alerts, got %d", tt.expected, len(notifier.GetAlerts()))
-				for i, alert := range notifier.GetAlerts() {
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
 					t.Logf("Alert %d: %+v", i, alert)
 				}
 			}
@@ -226,12 +222,8 @@ func TestHealthChecker_checkPod(t *testing.T) {
 	}
 }
 
-func TestHealthChecker_checkNode(t *testing.T) {
-	notifier := &MockNotifier{}
-	hc := &HealthChecker{
-		notifier:     notifier,
-		alertHistory: make(map[string]time.Time),
-	}
+func TestNodeChecker_Evaluate(t *testing.T) {
+	c := &nodeChecker{}
 
 	tests := []struct {
 		name     string
@@ -311,10 +303,8 @@ func TestHealthChecker_checkNode(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			notifier.ClearAlerts()
-			hc.checkNode(tt.node)
+			alerts := c.Evaluate(tt.node)
 
-			alerts := notifier.GetAlerts()
 			if len(alerts) != tt.expected {
 				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
 				for i, alert := range alerts {
@@ -328,12 +318,8 @@ func TestHealthChecker_checkNode(t *testing.T) {
 	}
 }
 
-func TestHealthChecker_checkDeployment(t *testing.T) {
-	notifier := &MockNotifier{}
-	hc := &HealthChecker{
-		notifier:     notifier,
-		alertHistory: make(map[string]time.Time),
-	}
+func TestDeploymentChecker_Evaluate(t *testing.T) {
+	c := &deploymentChecker{}
 
 	replicas := int32(3)
 	available := int32(1)
@@ -395,12 +381,11 @@ func TestHealthChecker_checkDeployment(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			notifier.ClearAlerts()
-			hc.checkDeployment(tt.deploy)
+			alerts := c.Evaluate(tt.deploy)
 
-			if len(notifier.GetAlerts()) != tt.expected {
-				t.Errorf("Expected %d alerts, got %d", tt.expected, len(notifier.GetAlerts()))
-				for i, alert := range notifier.GetAlerts() {
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
 					t.Logf("Alert %d: %+v", i, alert)
 				}
 			}
@@ -435,13 +420,91 @@ func TestHealthChecker_sendAlert_DuplicatePrevention(t *testing.T) {
 	}
 
 	// Wait for cooldown period and send again
-	hc.alertHistory[fmt.Sprintf("%s:%s:%s", alert.Level, alert.Resource, alert.Name)] = time.Now().Add(-6 * time.Minute)
+	hc.alertHistory[alert.Fingerprint()] = time.Now().Add(-6 * time.Minute)
 	hc.sendAlert(alert)
 	if len(notifier.GetAlerts()) != 2 {
 		t.Errorf("Expected 2 alerts after cooldown, got %d", len(notifier.GetAlerts()))
 	}
 }
 
+func TestHealthChecker_sendAlert_Silenced(t *testing.T) {
+	silences, err := silence.NewStore("")
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	sil, err := silences.Add(silence.Silence{
+		Matchers: []string{`node="rpi-1"`},
+		EndsAt:   time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	notifier := &MockNotifier{}
+	hc := &HealthChecker{
+		notifier:     notifier,
+		alertHistory: make(map[string]time.Time),
+	}
+	hc.UseSilences(silences)
+
+	alert := types.Alert{
+		Level:    types.AlertLevelCritical,
+		Resource: types.ResourceTypeNode,
+		Name:     "rpi-1",
+		Message:  "Node is not ready",
+		Labels:   map[string]string{types.LabelNode: "rpi-1"},
+	}
+
+	hc.sendAlert(alert)
+	if len(notifier.GetAlerts()) != 0 {
+		t.Fatalf("Expected the silenced alert to be dropped, got %d alerts", len(notifier.GetAlerts()))
+	}
+
+	// the alert goes out as soon as the silence ends, without waiting for the cooldown
+	if err := silences.Expire(sil.ID); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	hc.sendAlert(alert)
+	if len(notifier.GetAlerts()) != 1 {
+		t.Errorf("Expected 1 alert after the silence expired, got %d", len(notifier.GetAlerts()))
+	}
+}
+
+func TestHealthChecker_sendAlert_Templates(t *testing.T) {
+	templates, err := message.New("{{ .Level | upper }}: {{ .Name }}", "{{ .Message }}", "", "")
+	if err != nil {
+		t.Fatalf("message.New failed: %v", err)
+	}
+
+	notifier := &MockNotifier{}
+	routed := &MockNotifier{}
+	hc := &HealthChecker{
+		notifier:     notifier,
+		alertHistory: make(map[string]time.Time),
+	}
+	hc.UseRoute("team-payments", routed)
+	hc.UseTemplates("", templates)
+
+	alert := types.Alert{
+		Level:    types.AlertLevelError,
+		Resource: types.ResourceTypePod,
+		Name:     "default/api",
+		Message:  "Container is in CrashLoopBackOff",
+	}
+	hc.sendAlert(alert)
+
+	alert.Name = "payments/api"
+	alert.Route = "team-payments"
+	hc.sendAlert(alert)
+
+	if got := notifier.GetAlerts(); len(got) != 1 || got[0].Message != "ERROR: default/api\nContainer is in CrashLoopBackOff" {
+		t.Errorf("Expected the default notifier to use its templates, got %+v", got)
+	}
+	if got := routed.GetAlerts(); len(got) != 1 || got[0].Message != "❌ [pod] payments/api: Container is in CrashLoopBackOff" {
+		t.Errorf("Expected a route without templates to use the default format, got %+v", got)
+	}
+}
+
 func TestHealthChecker_Integration(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -496,17 +559,10 @@ func TestHealthChecker_Integration(t *testing.T) {
 
 	// Create fake client with test objects
 	client := fake.NewSimpleClientset(pod, node, deployment)
-	config := config.AppConfig{
-		Checker: struct {
-			CheckPods        bool `yaml:"check_pods"`
-			CheckNodes       bool `yaml:"check_nodes"`
-			CheckDeployments bool `yaml:"check_deployments"`
-		}{
-			CheckPods:        true,
-			CheckNodes:       true,
-			CheckDeployments: true,
-		},
-	}
+	config := config.AppConfig{}
+	config.Checker.CheckPods = true
+	config.Checker.CheckNodes = true
+	config.Checker.CheckDeployments = true
 	notifier := &MockNotifier{}
 
 	hc := NewHealthChecker(ctx, client, config, notifier)
@@ -0,0 +1,207 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/matcher"
+	"github.com/5iing/k8s-reliablity-informer/pkg/message"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	maintenanceSuppress  = "suppress"
+	maintenanceDowngrade = "downgrade"
+
+	// how often ended windows are looked for to send their summary
+	maintenanceInterval = 30 * time.Second
+	// alerts listed in a summary before the rest is only counted
+	maxSummarizedAlerts = 20
+)
+
+type maintenanceWindow struct {
+	name      string
+	schedule  cron.Schedule
+	duration  time.Duration
+	matchers  []matcher.Matcher
+	downgrade bool
+
+	// end of the occurrence alerts were last held back in, and those alerts
+	// by key, reset once its summary is sent
+	end      time.Time
+	affected map[string]types.Alert
+}
+
+func compileMaintenanceWindows(windows []config.MaintenanceWindow) ([]*maintenanceWindow, error) {
+	compiled := make([]*maintenanceWindow, 0, len(windows))
+	for i, w := range windows {
+		name := w.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i+1)
+		}
+
+		spec := w.Schedule
+		if w.Timezone != "" {
+			spec = fmt.Sprintf("CRON_TZ=%s %s", w.Timezone, spec)
+		}
+		schedule, err := cron.ParseStandard(spec)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %s: invalid schedule: %w", name, err)
+		}
+		if w.Duration <= 0 {
+			return nil, fmt.Errorf("maintenance window %s: duration is required", name)
+		}
+		matchers, err := matcher.ParseAll(w.Matchers)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %s: %w", name, err)
+		}
+		if len(matchers) == 0 {
+			return nil, fmt.Errorf("maintenance window %s: matchers are required", name)
+		}
+
+		var downgrade bool
+		switch w.Action {
+		case "", maintenanceSuppress:
+		case maintenanceDowngrade:
+			downgrade = true
+		default:
+			return nil, fmt.Errorf("maintenance window %s: unknown action %q", name, w.Action)
+		}
+
+		compiled = append(compiled, &maintenanceWindow{
+			name:      name,
+			schedule:  schedule,
+			duration:  w.Duration,
+			matchers:  matchers,
+			downgrade: downgrade,
+			affected:  make(map[string]types.Alert),
+		})
+	}
+	return compiled, nil
+}
+
+// openUntil returns the end of the occurrence of the window open at now
+func (w *maintenanceWindow) openUntil(now time.Time) (time.Time, bool) {
+	start := w.schedule.Next(now.Add(-w.duration))
+	if start.After(now) {
+		return time.Time{}, false
+	}
+	return start.Add(w.duration), true
+}
+
+// maintenance applies the open maintenance windows to alert. It returns
+// false when the alert is suppressed, otherwise the alert to send, which is
+// downgraded to info by downgrade windows. The caller holds hc.mu.
+func (hc *HealthChecker) maintenance(key string, alert types.Alert, now time.Time) (types.Alert, bool) {
+	suppress := false
+	downgradedBy := ""
+	for _, w := range hc.maintenanceWindows {
+		end, open := w.openUntil(now)
+		if !open || !matcher.MatchesAll(w.matchers, alert) {
+			continue
+		}
+		if !end.Equal(w.end) {
+			w.end = end
+			w.affected = make(map[string]types.Alert)
+		}
+		w.affected[key] = alert
+
+		if !w.downgrade {
+			suppress = true
+		} else if downgradedBy == "" {
+			downgradedBy = w.name
+		}
+	}
+	if suppress {
+		return alert, false
+	}
+
+	if downgradedBy != "" {
+		alert.Level = types.AlertLevelInfo
+		alert.Message = fmt.Sprintf("%s (maintenance window %s)", alert.Message, downgradedBy)
+	}
+	return alert, true
+}
+
+// watchMaintenanceWindows sends the summary of every window once it ends
+func (hc *HealthChecker) watchMaintenanceWindows(ctx context.Context) {
+	ticker := time.NewTicker(maintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, summary := range hc.endedMaintenanceWindows(now) {
+				hc.notify(&message.Data{Alert: summary, Status: message.StatusFiring})
+			}
+		}
+	}
+}
+
+// endedMaintenanceWindows returns a summary alert for every window that
+// ended with alerts held back, and resets those windows
+func (hc *HealthChecker) endedMaintenanceWindows(now time.Time) []types.Alert {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	var summaries []types.Alert
+	for _, w := range hc.maintenanceWindows {
+		if w.end.IsZero() || now.Before(w.end) {
+			continue
+		}
+		if len(w.affected) > 0 {
+			summary := w.summary()
+			summary.Cluster = hc.config.Cluster
+			summaries = append(summaries, summary)
+		}
+		w.end = time.Time{}
+		w.affected = make(map[string]types.Alert)
+	}
+	return summaries
+}
+
+func (w *maintenanceWindow) summary() types.Alert {
+	// keys are fingerprints, list the alerts in a readable order instead
+	alerts := make([]types.Alert, 0, len(w.affected))
+	for _, alert := range w.affected {
+		alerts = append(alerts, alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Resource != alerts[j].Resource {
+			return alerts[i].Resource < alerts[j].Resource
+		}
+		if alerts[i].Name != alerts[j].Name {
+			return alerts[i].Name < alerts[j].Name
+		}
+		return alerts[i].Level < alerts[j].Level
+	})
+
+	action := "suppressed"
+	if w.downgrade {
+		action = "downgraded to info"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Window ended, %d alerts were %s:", len(alerts), action)
+	for i, alert := range alerts {
+		if i == maxSummarizedAlerts {
+			fmt.Fprintf(&b, "\n... and %d more", len(alerts)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n%s", alert.FormatMessage())
+	}
+
+	return types.Alert{
+		Level:    types.AlertLevelInfo,
+		Resource: types.ResourceTypeMaintenance,
+		Name:     w.name,
+		Message:  b.String(),
+	}
+}
@@ -0,0 +1,153 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+func TestMaintenanceWindow_openUntil(t *testing.T) {
+	windows, err := compileMaintenanceWindows([]config.MaintenanceWindow{{
+		Name:     "pi-maintenance",
+		Schedule: "0 2 * * 0",
+		Duration: 2 * time.Hour,
+		Timezone: "Europe/Berlin",
+		Matchers: []string{`node=~"rpi-.*"`},
+	}})
+	if err != nil {
+		t.Fatalf("compileMaintenanceWindows failed: %v", err)
+	}
+	w := windows[0]
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("No time zone data: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected bool
+	}{
+		{name: "Sunday before the window", now: time.Date(2026, 10, 18, 1, 59, 0, 0, berlin), expected: false},
+		{name: "Sunday at the start", now: time.Date(2026, 10, 18, 2, 0, 0, 0, berlin), expected: true},
+		{name: "Sunday within the window", now: time.Date(2026, 10, 18, 3, 30, 0, 0, berlin), expected: true},
+		{name: "Sunday at the end", now: time.Date(2026, 10, 18, 4, 0, 0, 0, berlin), expected: false},
+		{name: "Monday", now: time.Date(2026, 10, 19, 3, 0, 0, 0, berlin), expected: false},
+		{name: "Sunday in UTC", now: time.Date(2026, 10, 18, 0, 30, 0, 0, time.UTC), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, open := w.openUntil(tt.now)
+			if open != tt.expected {
+				t.Fatalf("Expected open %v, got %v", tt.expected, open)
+			}
+			if open && !end.Equal(time.Date(2026, 10, 18, 4, 0, 0, 0, berlin)) {
+				t.Errorf("Expected the window to end at 04:00, got %v", end)
+			}
+		})
+	}
+}
+
+func TestCompileMaintenanceWindows_Invalid(t *testing.T) {
+	valid := config.MaintenanceWindow{
+		Schedule: "0 2 * * 0",
+		Duration: time.Hour,
+		Matchers: []string{`node="rpi-1"`},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(w *config.MaintenanceWindow)
+	}{
+		{name: "Bad schedule", modify: func(w *config.MaintenanceWindow) { w.Schedule = "sundays" }},
+		{name: "Unknown time zone", modify: func(w *config.MaintenanceWindow) { w.Timezone = "Mars/Olympus" }},
+		{name: "No duration", modify: func(w *config.MaintenanceWindow) { w.Duration = 0 }},
+		{name: "No matchers", modify: func(w *config.MaintenanceWindow) { w.Matchers = nil }},
+		{name: "Bad matcher", modify: func(w *config.MaintenanceWindow) { w.Matchers = []string{"node"} }},
+		{name: "Unknown action", modify: func(w *config.MaintenanceWindow) { w.Action = "mute" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := valid
+			tt.modify(&w)
+			if _, err := compileMaintenanceWindows([]config.MaintenanceWindow{w}); err == nil {
+				t.Error("Expected compileMaintenanceWindows to fail")
+			}
+		})
+	}
+}
+
+func TestHealthChecker_sendAlert_Maintenance(t *testing.T) {
+	nodeAlert := func(name string) types.Alert {
+		return types.Alert{
+			Level:    types.AlertLevelCritical,
+			Resource: types.ResourceTypeNode,
+			Name:     name,
+			Message:  "Node is not ready",
+			Labels:   map[string]string{types.LabelNode: name},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		action   string
+		expected int
+	}{
+		{name: "Suppress", action: "", expected: 1},
+		{name: "Downgrade", action: "downgrade", expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// opens every minute for an hour, so it is always open
+			windows, err := compileMaintenanceWindows([]config.MaintenanceWindow{{
+				Name:     "pi-maintenance",
+				Schedule: "* * * * *",
+				Duration: time.Hour,
+				Matchers: []string{`node=~"rpi-.*"`},
+				Action:   tt.action,
+			}})
+			if err != nil {
+				t.Fatalf("compileMaintenanceWindows failed: %v", err)
+			}
+
+			notifier := &MockNotifier{}
+			hc := &HealthChecker{
+				notifier:           notifier,
+				alertHistory:       make(map[string]time.Time),
+				maintenanceWindows: windows,
+			}
+
+			hc.sendAlert(nodeAlert("rpi-1"))
+			hc.sendAlert(nodeAlert("nuc-1"))
+
+			alerts := notifier.GetAlerts()
+			if len(alerts) != tt.expected {
+				t.Fatalf("Expected %d alerts, got %+v", tt.expected, alerts)
+			}
+			if tt.action == "downgrade" && !strings.Contains(alerts[0].Message, "(maintenance window pi-maintenance)") {
+				t.Errorf("Expected the alert to be downgraded, got %q", alerts[0].Message)
+			}
+
+			if summaries := hc.endedMaintenanceWindows(time.Now()); len(summaries) != 0 {
+				t.Errorf("Expected no summary while the window is open, got %+v", summaries)
+			}
+			summaries := hc.endedMaintenanceWindows(time.Now().Add(2 * time.Hour))
+			if len(summaries) != 1 {
+				t.Fatalf("Expected 1 summary once the window ended, got %d", len(summaries))
+			}
+			if !strings.Contains(summaries[0].Message, "1 alerts") || !strings.Contains(summaries[0].Message, "rpi-1") {
+				t.Errorf("Expected the summary to list rpi-1, got %q", summaries[0].Message)
+			}
+			if summaries := hc.endedMaintenanceWindows(time.Now().Add(2 * time.Hour)); len(summaries) != 0 {
+				t.Errorf("Expected the summary to be sent once, got %d", len(summaries))
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func init() {
+	Register("nodes", func(cfg config.AppConfig) Checker { return &nodeChecker{} })
+}
+
+type nodeChecker struct {
+	// used to list the workloads on a not ready node, nil lists none
+	pods   corelisters.PodLister
+	owners *ownerResolver
+}
+
+func (c *nodeChecker) Name() string     { return "nodes" }
+func (c *nodeChecker) Resource() string { return types.ResourceTypeNode }
+
+func (c *nodeChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	c.pods = src.Factory().Core().V1().Pods().Lister()
+	c.owners = newOwnerResolver(src.Factory())
+
+	return src.Factory().Core().V1().Nodes().Informer(), nil
+}
+
+func (c *nodeChecker) Evaluate(obj interface{}) []types.Alert {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return nil
+	}
+
+	var alerts []types.Alert
+
+	for _, cond := range node.Status.Conditions {
+		// not ready
+		if cond.Type == corev1.NodeReady && cond.Status != corev1.ConditionTrue {
+			msg := fmt.Sprintf("Node is not ready: %s", cond.Reason)
+			// the pod and deployment alerts this causes are suppressed
+			if c.pods != nil {
+				if impact := nodeImpact(podsOnNode(c.pods, node.Name), c.owners); impact != "" {
+					msg += ". " + impact
+				}
+			}
+
+			alerts = append(alerts, types.Alert{
+				Level:       types.AlertLevelCritical,
+				Resource:    types.ResourceTypeNode,
+				Name:        node.Name,
+				Message:     msg,
+				Annotations: descriptionAnnotations(cond.Message),
+			})
+		}
+
+		// mem pressure
+		if cond.Type == corev1.NodeMemoryPressure && cond.Status == corev1.ConditionTrue {
+			alerts = append(alerts, types.Alert{
+				Level:       types.AlertLevelWarning,
+				Resource:    types.ResourceTypeNode,
+				Name:        node.Name,
+				Message:     "Node has memory pressure",
+				Annotations: descriptionAnnotations(cond.Message),
+			})
+		}
+
+		// disk pressure
+		if cond.Type == corev1.NodeDiskPressure && cond.Status == corev1.ConditionTrue {
+			alerts = append(alerts, types.Alert{
+				Level:       types.AlertLevelWarning,
+				Resource:    types.ResourceTypeNode,
+				Name:        node.Name,
+				Message:     "Node has disk pressure",
+				Annotations: descriptionAnnotations(cond.Message),
+			})
+		}
+	}
+
+	return alerts
+}
@@ -0,0 +1,184 @@
+package checker
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/informers"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// annotations workloads tune their own alerting with
+const (
+	RestartThresholdAnnotation = "reliability-informer/restart-threshold"
+	SeverityAnnotation         = "reliability-informer/severity"
+	RouteAnnotation            = "reliability-informer/route"
+	RunbookAnnotation          = "reliability-informer/runbook-url"
+	DescriptionAnnotation      = "reliability-informer/description"
+)
+
+const defaultRestartThreshold = 5
+
+// overrides reads the annotations above. The object's own annotation wins
+// over the one on the Deployment owning it, which wins over its namespace's.
+type overrides struct {
+	owners      *ownerResolver
+	namespaces  corelisters.NamespaceLister
+	deployments appslisters.DeploymentLister
+
+	// invalid values already logged
+	reported map[string]bool
+	mu       sync.Mutex
+}
+
+// newOverrides registers the namespace and deployment informers and those
+// owners are resolved through. A nil factory only reads the object itself.
+func newOverrides(factory informers.SharedInformerFactory) *overrides {
+	o := &overrides{
+		owners:   newOwnerResolver(factory),
+		reported: make(map[string]bool),
+	}
+	if factory != nil {
+		o.namespaces = factory.Core().V1().Namespaces().Lister()
+		o.deployments = factory.Apps().V1().Deployments().Lister()
+	}
+	return o
+}
+
+// lookup returns the value of annotation and the object it was read from
+func (o *overrides) lookup(obj interface{}, annotation string) (value, source string, found bool) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", "", false
+	}
+
+	if value, ok := accessor.GetAnnotations()[annotation]; ok {
+		return value, describe(obj, accessor), true
+	}
+
+	if deploy := o.owningDeployment(accessor); deploy != nil {
+		if value, ok := deploy.Annotations[annotation]; ok {
+			return value, fmt.Sprintf("deployment %s/%s", deploy.Namespace, deploy.Name), true
+		}
+	}
+
+	if o.namespaces != nil && accessor.GetNamespace() != "" {
+		ns, err := o.namespaces.Get(accessor.GetNamespace())
+		if err == nil {
+			if value, ok := ns.Annotations[annotation]; ok {
+				return value, fmt.Sprintf("namespace %s", ns.Name), true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+// owningDeployment returns the Deployment owning a pod or replica set, if any
+func (o *overrides) owningDeployment(obj metav1.Object) *appsv1.Deployment {
+	if o.deployments == nil {
+		return nil
+	}
+
+	w, ok := o.owners.workloadOf(obj)
+	if !ok || w.Kind != "Deployment" {
+		return nil
+	}
+
+	deploy, err := o.deployments.Deployments(w.Namespace).Get(w.Name)
+	if err != nil {
+		return nil
+	}
+	return deploy
+}
+
+// restartThreshold returns the restart count above which a pod is alerted on
+func (o *overrides) restartThreshold(pod *corev1.Pod) int32 {
+	value, source, found := o.lookup(pod, RestartThresholdAnnotation)
+	if !found {
+		return defaultRestartThreshold
+	}
+
+	threshold, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || threshold < 0 {
+		o.invalid(source, RestartThresholdAnnotation, value, "must be a non-negative number")
+		return defaultRestartThreshold
+	}
+	return int32(threshold)
+}
+
+// severity returns the level all alerts on obj are sent with, "" to keep theirs
+func (o *overrides) severity(obj interface{}) string {
+	value, source, found := o.lookup(obj, SeverityAnnotation)
+	if !found {
+		return ""
+	}
+
+	if types.ValidLevel(value) {
+		return value
+	}
+	o.invalid(source, SeverityAnnotation, value, "must be critical, error, warning or info")
+	return ""
+}
+
+// route returns the route alerts on obj are sent to, "" for the default one
+func (o *overrides) route(obj interface{}) (route, source string) {
+	route, source, _ = o.lookup(obj, RouteAnnotation)
+	return route, source
+}
+
+// runbookURL returns the runbook linked from alerts on obj, if any
+func (o *overrides) runbookURL(obj interface{}) string {
+	value, _, _ := o.lookup(obj, RunbookAnnotation)
+	return value
+}
+
+// description returns the description of alerts on obj the checker does not
+// describe itself, if any
+func (o *overrides) description(obj interface{}) string {
+	value, _, _ := o.lookup(obj, DescriptionAnnotation)
+	return value
+}
+
+// invalid logs a bad annotation value once rather than on every resync
+func (o *overrides) invalid(source, annotation, value, reason string) {
+	key := fmt.Sprintf("%s:%s:%s", source, annotation, value)
+
+	o.mu.Lock()
+	if o.reported[key] {
+		o.mu.Unlock()
+		return
+	}
+	o.reported[key] = true
+	o.mu.Unlock()
+
+	fmt.Printf("Ignoring annotation %s=%q on %s: %s\n", annotation, value, source, reason)
+}
+
+// describe names an object for log messages, e.g. "pod default/api-7d9f"
+func describe(obj interface{}, accessor metav1.Object) string {
+	// informer objects come without their TypeMeta
+	kind := "object"
+	if t := reflect.TypeOf(obj); t.Kind() == reflect.Ptr {
+		kind = strings.ToLower(t.Elem().Name())
+	}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		kind = strings.ToLower(u.GetKind())
+	}
+
+	name := accessor.GetName()
+	if accessor.GetNamespace() != "" {
+		name = fmt.Sprintf("%s/%s", accessor.GetNamespace(), name)
+	}
+	return fmt.Sprintf("%s %s", kind, name)
+}
@@ -0,0 +1,203 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestOverrides_lookup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	controller := true
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "payments",
+		Annotations: map[string]string{RestartThresholdAnnotation: "10", SeverityAnnotation: "warning"},
+	}}
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:        "api",
+		Namespace:   "payments",
+		Annotations: map[string]string{RestartThresholdAnnotation: "20"},
+	}}
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:            "api-7d9f",
+		Namespace:       "payments",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "api", Controller: &controller}},
+	}}
+	newPod := func(annotations map[string]string, owners ...metav1.OwnerReference) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:            "api-7d9f-x2k4",
+			Namespace:       "payments",
+			Annotations:     annotations,
+			OwnerReferences: owners,
+		}}
+	}
+	owned := metav1.OwnerReference{Kind: "ReplicaSet", Name: "api-7d9f", Controller: &controller}
+
+	client := fake.NewSimpleClientset(ns, deploy, rs)
+	hc := NewHealthChecker(ctx, client, config.AppConfig{}, nil)
+	o := newOverrides(hc.factory)
+	hc.factory.Start(ctx.Done())
+	hc.factory.WaitForCacheSync(ctx.Done())
+
+	tests := []struct {
+		name      string
+		pod       *corev1.Pod
+		threshold int32
+		severity  string
+	}{
+		{
+			name:      "Pod annotation wins",
+			pod:       newPod(map[string]string{RestartThresholdAnnotation: "30"}, owned),
+			threshold: 30,
+			severity:  types.AlertLevelWarning,
+		},
+		{
+			name:      "Deployment annotation wins over namespace",
+			pod:       newPod(nil, owned),
+			threshold: 20,
+			severity:  types.AlertLevelWarning,
+		},
+		{
+			name:      "Namespace annotation",
+			pod:       newPod(nil),
+			threshold: 10,
+			severity:  types.AlertLevelWarning,
+		},
+		{
+			name:      "Invalid values fall back",
+			pod:       newPod(map[string]string{RestartThresholdAnnotation: "lots", SeverityAnnotation: "page-me"}),
+			threshold: defaultRestartThreshold,
+			severity:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := o.restartThreshold(tt.pod); got != tt.threshold {
+				t.Errorf("Expected restart threshold %d, got %d", tt.threshold, got)
+			}
+			if got := o.severity(tt.pod); got != tt.severity {
+				t.Errorf("Expected severity %q, got %q", tt.severity, got)
+			}
+		})
+	}
+}
+
+func TestHealthChecker_applyOverrides(t *testing.T) {
+	paging := &MockNotifier{}
+	notifier := &MockNotifier{}
+	hc := &HealthChecker{
+		notifier:     notifier,
+		alertHistory: make(map[string]time.Time),
+		overrides:    newOverrides(nil),
+	}
+	hc.UseRoute("team-payments", paging)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "api",
+		Namespace: "payments",
+		Annotations: map[string]string{
+			SeverityAnnotation: types.AlertLevelCritical,
+			RouteAnnotation:    "team-payments",
+		},
+	}}
+
+	alert := hc.applyOverrides(types.Alert{
+		Level:    types.AlertLevelWarning,
+		Resource: types.ResourceTypePod,
+		Name:     "payments/api",
+		Message:  "High restart count: 8",
+	}, pod)
+	if alert.Level != types.AlertLevelCritical || alert.Route != "team-payments" {
+		t.Fatalf("Expected critical alert routed to team-payments, got %+v", alert)
+	}
+
+	hc.sendAlert(alert)
+	if len(paging.GetAlerts()) != 1 || len(notifier.GetAlerts()) != 0 {
+		t.Errorf("Expected the alert on the route only, got %d routed and %d default",
+			len(paging.GetAlerts()), len(notifier.GetAlerts()))
+	}
+
+	// unknown routes go to the default notifier
+	pod.Annotations[RouteAnnotation] = "team-unknown"
+	if alert := hc.applyOverrides(types.Alert{}, pod); alert.Route != "" {
+		t.Errorf("Expected no route, got %q", alert.Route)
+	}
+}
+
+func TestHealthChecker_prepare(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	controller := true
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name: "payments",
+		Annotations: map[string]string{
+			RunbookAnnotation:     "https://runbooks.example.com/payments",
+			DescriptionAnnotation: "Payment API, owned by the payments team",
+		},
+	}}
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "payments"}}
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:            "api-7d9f",
+		Namespace:       "payments",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "api", Controller: &controller}},
+	}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:            "api-7d9f-x2k4",
+		Namespace:       "payments",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-7d9f", Controller: &controller}},
+	}}
+
+	client := fake.NewSimpleClientset(ns, deploy, rs)
+	hc := NewHealthChecker(ctx, client, config.AppConfig{}, nil)
+	hc.overrides = newOverrides(hc.factory)
+	hc.factory.Start(ctx.Done())
+	hc.factory.WaitForCacheSync(ctx.Done())
+
+	tests := []struct {
+		name        string
+		alert       types.Alert
+		description string
+	}{
+		{
+			name:        "Description from the annotation",
+			alert:       types.Alert{Level: types.AlertLevelError, Resource: types.ResourceTypePod, Name: "payments/api"},
+			description: "Payment API, owned by the payments team",
+		},
+		{
+			name: "Description from the checker wins",
+			alert: types.Alert{
+				Level:       types.AlertLevelError,
+				Resource:    types.ResourceTypePod,
+				Name:        "payments/api",
+				Annotations: map[string]string{types.AnnotationDescription: "Back-off restarting failed container"},
+			},
+			description: "Back-off restarting failed container",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := hc.prepare(tt.alert, pod)
+			if alert.Labels[types.LabelNamespace] != "payments" || alert.Labels[types.LabelOwner] != "payments/deployment/api" {
+				t.Errorf("Expected namespace and owner labels, got %v", alert.Labels)
+			}
+			if got := alert.Annotations[types.AnnotationRunbookURL]; got != "https://runbooks.example.com/payments" {
+				t.Errorf("Expected the namespace runbook, got %q", got)
+			}
+			if got := alert.Annotations[types.AnnotationDescription]; got != tt.description {
+				t.Errorf("Expected description %q, got %q", tt.description, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+)
+
+// workload is the top level controller owning an object, e.g. the
+// Deployment of a pod's ReplicaSet
+type workload struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (w workload) String() string {
+	return fmt.Sprintf("%s/%s/%s", w.Namespace, strings.ToLower(w.Kind), w.Name)
+}
+
+// ownerResolver follows controller references up to the workload. ReplicaSets
+// are resolved to their Deployment and Jobs to their CronJob, other
+// controllers such as StatefulSets and DaemonSets are workloads themselves.
+type ownerResolver struct {
+	replicaSets appslisters.ReplicaSetLister
+	jobs        batchlisters.JobLister
+}
+
+// newOwnerResolver registers the replica set and job informers. A nil factory
+// stops at the direct controller.
+func newOwnerResolver(factory informers.SharedInformerFactory) *ownerResolver {
+	r := &ownerResolver{}
+	if factory != nil {
+		r.replicaSets = factory.Apps().V1().ReplicaSets().Lister()
+		r.jobs = factory.Batch().V1().Jobs().Lister()
+	}
+	return r
+}
+
+// workloadOf returns the workload owning obj, false when it has no controller
+func (r *ownerResolver) workloadOf(obj metav1.Object) (workload, bool) {
+	ref := metav1.GetControllerOf(obj)
+	if ref == nil {
+		return workload{}, false
+	}
+	w := workload{Kind: ref.Kind, Namespace: obj.GetNamespace(), Name: ref.Name}
+
+	var parent metav1.Object
+	switch {
+	case ref.Kind == "ReplicaSet" && r.replicaSets != nil:
+		if rs, err := r.replicaSets.ReplicaSets(w.Namespace).Get(ref.Name); err == nil {
+			parent = rs
+		}
+	case ref.Kind == "Job" && r.jobs != nil:
+		if job, err := r.jobs.Jobs(w.Namespace).Get(ref.Name); err == nil {
+			parent = job
+		}
+	}
+
+	if parent != nil {
+		if ref := metav1.GetControllerOf(parent); ref != nil {
+			w.Kind, w.Name = ref.Kind, ref.Name
+		}
+	}
+	return w, true
+}
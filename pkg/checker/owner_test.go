@@ -0,0 +1,67 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func controllerRef(kind, name string) []metav1.OwnerReference {
+	controller := true
+	return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+}
+
+func TestOwnerResolver_workloadOf(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := fake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "api-7d9f", Namespace: "default", OwnerReferences: controllerRef("Deployment", "api"),
+		}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "bare", Namespace: "default"}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name: "backup-28391", Namespace: "default", OwnerReferences: controllerRef("CronJob", "backup"),
+		}},
+	)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	r := newOwnerResolver(factory)
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+
+	newPod := func(owners []metav1.OwnerReference) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "default", OwnerReferences: owners}}
+	}
+
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected string
+		owned    bool
+	}{
+		{name: "Deployment through ReplicaSet", pod: newPod(controllerRef("ReplicaSet", "api-7d9f")), expected: "default/deployment/api", owned: true},
+		{name: "ReplicaSet without Deployment", pod: newPod(controllerRef("ReplicaSet", "bare")), expected: "default/replicaset/bare", owned: true},
+		{name: "CronJob through Job", pod: newPod(controllerRef("Job", "backup-28391")), expected: "default/cronjob/backup", owned: true},
+		{name: "StatefulSet", pod: newPod(controllerRef("StatefulSet", "db")), expected: "default/statefulset/db", owned: true},
+		{name: "Bare pod", pod: newPod(nil), owned: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, owned := r.workloadOf(tt.pod)
+			if owned != tt.owned {
+				t.Fatalf("Expected owned %v, got %v", tt.owned, owned)
+			}
+			if owned && w.String() != tt.expected {
+				t.Errorf("Expected workload %s, got %s", tt.expected, w)
+			}
+		})
+	}
+}
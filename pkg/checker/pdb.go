@@ -0,0 +1,92 @@
+package checker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const defaultPDBGracePeriod = 10 * time.Minute
+
+func init() {
+	Register("pdbs", func(cfg config.AppConfig) Checker {
+		grace := cfg.Checker.PDBs.GracePeriod
+		if grace <= 0 {
+			grace = defaultPDBGracePeriod
+		}
+		return &pdbChecker{grace: grace}
+	})
+}
+
+type pdbChecker struct {
+	grace     time.Duration
+	unhealthy graceTracker
+	blocked   graceTracker
+}
+
+func (c *pdbChecker) Name() string     { return "pdbs" }
+func (c *pdbChecker) Resource() string { return types.ResourceTypePDB }
+
+func (c *pdbChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	return src.Factory().Policy().V1().PodDisruptionBudgets().Informer(), nil
+}
+
+func (c *pdbChecker) Evaluate(obj interface{}) []types.Alert {
+	pdb, ok := obj.(*policyv1.PodDisruptionBudget)
+	if !ok {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s/%s", pdb.Namespace, pdb.Name)
+
+	// nothing selected, nothing to protect
+	if pdb.Status.ExpectedPods == 0 {
+		c.Forget(pdb)
+		return nil
+	}
+
+	var alerts []types.Alert
+
+	// app lost redundancy
+	if pdb.Status.CurrentHealthy < pdb.Status.DesiredHealthy {
+		if since := c.unhealthy.unhealthyFor(name); since >= c.grace {
+			alerts = append(alerts, types.Alert{
+				Level:    types.AlertLevelError,
+				Resource: types.ResourceTypePDB,
+				Name:     name,
+				Message: fmt.Sprintf("Budget violated for %s: %d/%d healthy pods",
+					since.Round(time.Second), pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy),
+			})
+		}
+	} else {
+		c.unhealthy.clear(name)
+	}
+
+	// node drains will block
+	if pdb.Status.DisruptionsAllowed == 0 {
+		if since := c.blocked.unhealthyFor(name); since >= c.grace {
+			alerts = append(alerts, types.Alert{
+				Level:    types.AlertLevelWarning,
+				Resource: types.ResourceTypePDB,
+				Name:     name,
+				Message:  fmt.Sprintf("No disruptions allowed for %s, node drains will block", since.Round(time.Second)),
+			})
+		}
+	} else {
+		c.blocked.clear(name)
+	}
+
+	return alerts
+}
+
+func (c *pdbChecker) Forget(obj interface{}) {
+	if pdb, ok := obj.(*policyv1.PodDisruptionBudget); ok {
+		name := fmt.Sprintf("%s/%s", pdb.Namespace, pdb.Name)
+		c.unhealthy.clear(name)
+		c.blocked.clear(name)
+	}
+}
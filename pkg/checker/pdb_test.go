@@ -0,0 +1,79 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPDBChecker_Evaluate(t *testing.T) {
+	c := &pdbChecker{grace: 10 * time.Minute}
+
+	newPDB := func(expected, current, desired, allowed int32) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Status: policyv1.PodDisruptionBudgetStatus{
+				ExpectedPods:       expected,
+				CurrentHealthy:     current,
+				DesiredHealthy:     desired,
+				DisruptionsAllowed: allowed,
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		pdb      *policyv1.PodDisruptionBudget
+		since    time.Duration
+		expected int
+	}{
+		{
+			name:     "Budget violated past grace period",
+			pdb:      newPDB(3, 1, 2, 0),
+			since:    time.Hour,
+			expected: 2,
+		},
+		{
+			name:     "Budget violated within grace period",
+			pdb:      newPDB(3, 1, 2, 0),
+			since:    time.Minute,
+			expected: 0,
+		},
+		{
+			name:     "No disruptions allowed past grace period",
+			pdb:      newPDB(2, 2, 2, 0),
+			since:    time.Hour,
+			expected: 1,
+		},
+		{
+			name:     "Healthy budget",
+			pdb:      newPDB(3, 3, 2, 1),
+			since:    time.Hour,
+			expected: 0,
+		},
+		{
+			name:     "No pods selected",
+			pdb:      newPDB(0, 0, 0, 0),
+			since:    time.Hour,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.unhealthy.setSince("default/api", time.Now().Add(-tt.since))
+			c.blocked.setSince("default/api", time.Now().Add(-tt.since))
+
+			alerts := c.Evaluate(tt.pdb)
+
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// pods named in a grouped alert before the rest are only counted
+const maxListedPods = 5
+
+func init() {
+	Register("pods", func(cfg config.AppConfig) Checker {
+		return &podChecker{correlate: nodesChecked(cfg)}
+	})
+}
+
+// podChecker reports pods owned by a workload as one alert per workload and
+// level, so a crashlooping ReplicaSet is one alert rather than one per pod
+type podChecker struct {
+	// nil reports every pod on its own
+	pods   corelisters.PodLister
+	owners *ownerResolver
+	// restart threshold annotations, nil uses the default
+	overrides *overrides
+
+	// leave pods on not ready nodes to the node alert
+	correlate bool
+	nodes     corelisters.NodeLister
+
+	// filters the sibling pods of a grouped alert, nil keeps all
+	inScope func(obj interface{}) bool
+}
+
+// podProblem is one thing wrong with a pod
+type podProblem struct {
+	level string
+	// groups the problem across the pods of a workload
+	summary string
+	// alert message when the pod is reported on its own
+	message string
+	// shown after the pod name in a grouped alert
+	detail string
+	// what Kubernetes reports about the problem, if anything
+	description string
+}
+
+func (c *podChecker) Name() string     { return "pods" }
+func (c *podChecker) Resource() string { return types.ResourceTypePod }
+
+func (c *podChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	c.pods = src.Factory().Core().V1().Pods().Lister()
+	c.owners = newOwnerResolver(src.Factory())
+	c.overrides = newOverrides(src.Factory())
+	c.inScope = src.InScope
+	if c.correlate {
+		c.nodes = src.Factory().Core().V1().Nodes().Lister()
+	}
+
+	return src.Factory().Core().V1().Pods().Informer(), nil
+}
+
+func (c *podChecker) Evaluate(obj interface{}) []types.Alert {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+
+	problems := c.problems(pod)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	if c.pods == nil || c.owners == nil {
+		return podAlerts(pod, problems)
+	}
+	w, owned := c.owners.workloadOf(pod)
+	if !owned {
+		return podAlerts(pod, problems)
+	}
+
+	siblings, err := c.pods.Pods(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		return podAlerts(pod, problems)
+	}
+
+	seen := map[string]bool{pod.Name: true}
+	// level -> summary -> affected pods
+	grouped := make(map[string]map[string][]string)
+	// level -> first description found, the evaluated pod's if it has one
+	descriptions := make(map[string]string)
+	addProblems := func(p *corev1.Pod, problems []podProblem) {
+		for _, problem := range problems {
+			if grouped[problem.level] == nil {
+				grouped[problem.level] = make(map[string][]string)
+			}
+			if descriptions[problem.level] == "" {
+				descriptions[problem.level] = problem.description
+			}
+			entry := p.Name
+			if problem.detail != "" {
+				entry = fmt.Sprintf("%s (%s)", p.Name, problem.detail)
+			}
+			grouped[problem.level][problem.summary] = append(grouped[problem.level][problem.summary], entry)
+		}
+	}
+
+	// the lister may hold an older copy of pod, use the one being evaluated
+	addProblems(pod, problems)
+	for _, sibling := range siblings {
+		if seen[sibling.Name] {
+			continue
+		}
+		if sw, ok := c.owners.workloadOf(sibling); !ok || sw != w {
+			continue
+		}
+		if c.inScope != nil && !c.inScope(sibling) {
+			continue
+		}
+		seen[sibling.Name] = true
+		addProblems(sibling, c.problems(sibling))
+	}
+
+	var alerts []types.Alert
+	for _, level := range []string{types.AlertLevelCritical, types.AlertLevelError, types.AlertLevelWarning, types.AlertLevelInfo} {
+		bySummary, ok := grouped[level]
+		if !ok {
+			continue
+		}
+
+		summaries := make([]string, 0, len(bySummary))
+		for summary := range bySummary {
+			summaries = append(summaries, summary)
+		}
+		sort.Strings(summaries)
+
+		parts := make([]string, 0, len(summaries))
+		for _, summary := range summaries {
+			parts = append(parts, fmt.Sprintf("%s in %s", summary, listPods(bySummary[summary])))
+		}
+
+		// no node label, the affected pods change nodes while the alert
+		// fires and it has to keep its fingerprint. Pods on a not ready
+		// node are left to the node alert by problems instead.
+		alerts = append(alerts, types.Alert{
+			Level:       level,
+			Resource:    types.ResourceTypePod,
+			Name:        w.String(),
+			Message:     strings.Join(parts, "; "),
+			Annotations: descriptionAnnotations(descriptions[level]),
+		})
+	}
+
+	return alerts
+}
+
+// problems lists what is wrong with a single pod
+func (c *podChecker) problems(pod *corev1.Pod) []podProblem {
+	// the node alert lists the pods it takes down
+	if nodeDown(c.nodes, pod.Spec.NodeName) {
+		return nil
+	}
+
+	threshold := int32(defaultRestartThreshold)
+	if c.overrides != nil {
+		threshold = c.overrides.restartThreshold(pod)
+	}
+
+	var problems []podProblem
+
+	//pod failed
+	if pod.Status.Phase == corev1.PodFailed {
+		problems = append(problems, podProblem{
+			level:       types.AlertLevelError,
+			summary:     "Pod failed",
+			message:     fmt.Sprintf("Pod failed: %s", pod.Status.Reason),
+			detail:      pod.Status.Reason,
+			description: pod.Status.Message,
+		})
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		// crashloopfallback
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+			problems = append(problems, podProblem{
+				level:       types.AlertLevelError,
+				summary:     "CrashLoopBackOff",
+				message:     "Container is in CrashLoopBackOff",
+				description: cs.State.Waiting.Message,
+			})
+		}
+
+		// restart
+		if cs.RestartCount > threshold {
+			problems = append(problems, podProblem{
+				level:   types.AlertLevelWarning,
+				summary: "High restart count",
+				message: fmt.Sprintf("High restart count: %d", cs.RestartCount),
+				detail:  fmt.Sprintf("%d restarts", cs.RestartCount),
+			})
+		}
+
+		// pod waiting or image pull back of ffff
+		if cs.State.Waiting != nil &&
+			(cs.State.Waiting.Reason == "ImagePullBackOff" || cs.State.Waiting.Reason == "ErrImagePull") {
+			problems = append(problems, podProblem{
+				level:       types.AlertLevelError,
+				summary:     fmt.Sprintf("Image pull failed: %s", cs.State.Waiting.Reason),
+				message:     fmt.Sprintf("Image pull failed: %s", cs.State.Waiting.Reason),
+				description: cs.State.Waiting.Message,
+			})
+		}
+	}
+
+	return problems
+}
+
+// podAlerts reports the problems of a pod without a workload on their own
+func podAlerts(pod *corev1.Pod, problems []podProblem) []types.Alert {
+	alerts := make([]types.Alert, 0, len(problems))
+	for _, problem := range problems {
+		alerts = append(alerts, types.Alert{
+			Level:       problem.level,
+			Resource:    types.ResourceTypePod,
+			Name:        fmt.Sprintf("%s/%s", pod.Namespace, pod.Name),
+			Message:     problem.message,
+			Labels:      map[string]string{types.LabelNode: pod.Spec.NodeName},
+			Annotations: descriptionAnnotations(problem.description),
+		})
+	}
+	return alerts
+}
+
+// listPods formats the affected pods, e.g. "2 pods: api-1, api-2"
+func listPods(pods []string) string {
+	sort.Strings(pods)
+
+	noun := "pods"
+	if len(pods) == 1 {
+		noun = "pod"
+	}
+
+	listed := pods
+	more := ""
+	if len(pods) > maxListedPods {
+		listed = pods[:maxListedPods]
+		more = fmt.Sprintf(" and %d more", len(pods)-maxListedPods)
+	}
+	return fmt.Sprintf("%d %s: %s%s", len(pods), noun, strings.Join(listed, ", "), more)
+}
@@ -0,0 +1,150 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodChecker_Evaluate_GroupsByWorkload(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	crashing := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				OwnerReferences: controllerRef("ReplicaSet", "api-7d9f"),
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					RestartCount: 8,
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{
+							Reason:  "CrashLoopBackOff",
+							Message: "back-off 5m0s restarting failed container",
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "api-7d9f", Namespace: "default", OwnerReferences: controllerRef("Deployment", "api"),
+		}},
+		// healthy pod of the same workload
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "api-7d9f-ok", Namespace: "default", OwnerReferences: controllerRef("ReplicaSet", "api-7d9f"),
+		}},
+		// crashing pod of another workload
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", OwnerReferences: controllerRef("StatefulSet", "web")},
+			Status:     crashing("web-1").Status,
+		},
+	}
+	for i := 0; i < 7; i++ {
+		objects = append(objects, crashing(fmt.Sprintf("api-7d9f-%d", i)))
+	}
+
+	c := &podChecker{}
+	startChecker(ctx, t, c, fake.NewSimpleClientset(objects...))
+
+	alerts := c.Evaluate(crashing("api-7d9f-0"))
+	if len(alerts) != 2 {
+		t.Fatalf("Expected an error and a warning alert, got %+v", alerts)
+	}
+
+	for _, alert := range alerts {
+		if alert.Name != "default/deployment/api" {
+			t.Errorf("Expected alert for the deployment, got %s", alert.Name)
+		}
+	}
+	if alerts[0].Level != types.AlertLevelError ||
+		!strings.HasPrefix(alerts[0].Message, "CrashLoopBackOff in 7 pods: api-7d9f-0, api-7d9f-1") ||
+		!strings.HasSuffix(alerts[0].Message, "and 2 more") {
+		t.Errorf("Unexpected error alert %+v", alerts[0])
+	}
+	if got := alerts[0].Annotations[types.AnnotationDescription]; got != "back-off 5m0s restarting failed container" {
+		t.Errorf("Expected the kubelet message as description, got %q", got)
+	}
+	if alerts[1].Level != types.AlertLevelWarning || !strings.Contains(alerts[1].Message, "api-7d9f-0 (8 restarts)") {
+		t.Errorf("Unexpected warning alert %+v", alerts[1])
+	}
+
+	// siblings out of scope are left out
+	c.inScope = func(obj interface{}) bool { return obj.(*corev1.Pod).Name != "api-7d9f-1" }
+	alerts = c.Evaluate(crashing("api-7d9f-0"))
+	if len(alerts) != 2 || !strings.HasPrefix(alerts[0].Message, "CrashLoopBackOff in 6 pods: api-7d9f-0, api-7d9f-2") {
+		t.Errorf("Expected the ignored pod to be left out, got %+v", alerts)
+	}
+
+	// pods without a controller are reported on their own
+	bare := crashing("debug")
+	bare.OwnerReferences = nil
+	alerts = c.Evaluate(bare)
+	if len(alerts) != 2 || alerts[0].Name != "default/debug" || alerts[0].Annotations[types.AnnotationDescription] == "" {
+		t.Errorf("Expected per pod alerts for a bare pod, got %+v", alerts)
+	}
+}
+
+func TestPodChecker_Evaluate_Fingerprint(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	crashing := func(name, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", OwnerReferences: controllerRef("StatefulSet", "db")},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				}},
+			},
+		}
+	}
+
+	client := fake.NewSimpleClientset(crashing("db-0", "rpi-1"))
+	c := &podChecker{}
+	startChecker(ctx, t, c, client)
+
+	alerts := c.Evaluate(crashing("db-0", "rpi-1"))
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %+v", alerts)
+	}
+	first := alerts[0].Fingerprint()
+
+	// the crashloop spreads to a pod on another node
+	if _, err := client.CoreV1().Pods("default").Create(ctx, crashing("db-1", "rpi-2"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create pod: %v", err)
+	}
+	for {
+		if _, err := c.pods.Pods("default").Get("db-1"); err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("Timed out waiting for the pod to be cached")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	alerts = c.Evaluate(crashing("db-0", "rpi-1"))
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "2 pods") {
+		t.Fatalf("Expected 1 alert for both pods, got %+v", alerts)
+	}
+	if got := alerts[0].Fingerprint(); got != first {
+		t.Errorf("Expected the fingerprint to stay %s, got %s", first, got)
+	}
+}
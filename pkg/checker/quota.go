@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	defaultQuotaWarningPercent = 80
+	defaultQuotaErrorPercent   = 95
+)
+
+func init() {
+	Register("quotas", func(cfg config.AppConfig) Checker {
+		warnAt := cfg.Checker.Quotas.WarningPercent
+		if warnAt <= 0 {
+			warnAt = defaultQuotaWarningPercent
+		}
+		errorAt := cfg.Checker.Quotas.ErrorPercent
+		if errorAt <= 0 {
+			errorAt = defaultQuotaErrorPercent
+		}
+		return &quotaChecker{warnAt: warnAt, errorAt: errorAt}
+	})
+}
+
+type quotaChecker struct {
+	warnAt  float64
+	errorAt float64
+}
+
+func (c *quotaChecker) Name() string     { return "quotas" }
+func (c *quotaChecker) Resource() string { return types.ResourceTypeQuota }
+
+func (c *quotaChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	return src.Factory().Core().V1().ResourceQuotas().Informer(), nil
+}
+
+func (c *quotaChecker) Evaluate(obj interface{}) []types.Alert {
+	quota, ok := obj.(*corev1.ResourceQuota)
+	if !ok {
+		return nil
+	}
+
+	var warnings, errors []string
+	for resource, hard := range quota.Status.Hard {
+		used, ok := quota.Status.Used[resource]
+		if !ok || hard.IsZero() {
+			continue
+		}
+
+		percent := used.AsApproximateFloat64() / hard.AsApproximateFloat64() * 100
+		usage := fmt.Sprintf("%s %s/%s (%.0f%%)", resource, used.String(), hard.String(), percent)
+
+		switch {
+		case percent >= c.errorAt:
+			errors = append(errors, usage)
+		case percent >= c.warnAt:
+			warnings = append(warnings, usage)
+		}
+	}
+
+	name := fmt.Sprintf("%s/%s", quota.Namespace, quota.Name)
+	var alerts []types.Alert
+
+	if len(errors) > 0 {
+		sort.Strings(errors)
+		alerts = append(alerts, types.Alert{
+			Level:    types.AlertLevelError,
+			Resource: types.ResourceTypeQuota,
+			Name:     name,
+			Message:  fmt.Sprintf("Quota nearly exhausted: %s", strings.Join(errors, ", ")),
+		})
+	}
+
+	if len(warnings) > 0 {
+		sort.Strings(warnings)
+		alerts = append(alerts, types.Alert{
+			Level:    types.AlertLevelWarning,
+			Resource: types.ResourceTypeQuota,
+			Name:     name,
+			Message:  fmt.Sprintf("Quota usage high: %s", strings.Join(warnings, ", ")),
+		})
+	}
+
+	return alerts
+}
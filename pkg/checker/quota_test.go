@@ -0,0 +1,86 @@
+package checker
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestQuotaChecker_Evaluate(t *testing.T) {
+	c := &quotaChecker{warnAt: 80, errorAt: 95}
+
+	newQuota := func(hard, used corev1.ResourceList) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "team-a"},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		quota    *corev1.ResourceQuota
+		expected int
+	}{
+		{
+			name: "Usage below warning",
+			quota: newQuota(
+				corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("4Gi")},
+				corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("1Gi")},
+			),
+			expected: 0,
+		},
+		{
+			name: "Usage above warning",
+			quota: newQuota(
+				corev1.ResourceList{corev1.ResourceLimitsCPU: resource.MustParse("4")},
+				corev1.ResourceList{corev1.ResourceLimitsCPU: resource.MustParse("3500m")},
+			),
+			expected: 1,
+		},
+		{
+			name: "Usage above error",
+			quota: newQuota(
+				corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+			),
+			expected: 1,
+		},
+		{
+			name: "Warning and error on different resources",
+			quota: newQuota(
+				corev1.ResourceList{
+					corev1.ResourcePods:         resource.MustParse("10"),
+					corev1.ResourceLimitsMemory: resource.MustParse("4Gi"),
+				},
+				corev1.ResourceList{
+					corev1.ResourcePods:         resource.MustParse("10"),
+					corev1.ResourceLimitsMemory: resource.MustParse("3.5Gi"),
+				},
+			),
+			expected: 2,
+		},
+		{
+			name: "Zero hard limit",
+			quota: newQuota(
+				corev1.ResourceList{corev1.ResourceServicesLoadBalancers: resource.MustParse("0")},
+				corev1.ResourceList{corev1.ResourceServicesLoadBalancers: resource.MustParse("0")},
+			),
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := c.Evaluate(tt.quota)
+
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Checker evaluates the objects of one informer and reports what is wrong
+// with them. Add and update events, including resyncs, are passed to Evaluate.
+type Checker interface {
+	// Name is the name the check is enabled by in config.Checker.Enabled
+	Name() string
+	// Resource is the resource type of the alerts, e.g. types.ResourceTypePod
+	Resource() string
+	// Informer returns the informer whose objects are evaluated. Checkers
+	// that read other resources register and keep their listers here.
+	Informer(src InformerSource) (cache.SharedIndexInformer, error)
+	// Evaluate returns the alerts for obj, none when it is healthy
+	Evaluate(obj interface{}) []types.Alert
+}
+
+// Forgetter is implemented by checkers that keep state per object, which is
+// dropped when the object is deleted
+type Forgetter interface {
+	Forget(obj interface{})
+}
+
+// InformerSource hands out the informers checkers read from. Everything
+// registered before Start returns is started and synced by the HealthChecker.
+type InformerSource interface {
+	Factory() informers.SharedInformerFactory
+	// informers for kubernetes.io/tls secrets only
+	TLSSecrets() coreinformers.SecretInformer
+	// for the few objects looked up one at a time instead of watched
+	Client() kubernetes.Interface
+	// nil when no dynamic client is set
+	DynamicFactory() dynamicinformer.DynamicSharedInformerFactory
+	// InScope reports whether obj is checked at all, for checkers that look
+	// at other objects than the one evaluated
+	InScope(obj interface{}) bool
+}
+
+// CheckerFactory builds a checker from the app config
+type CheckerFactory func(cfg config.AppConfig) Checker
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]CheckerFactory)
+)
+
+// Register makes a checker available to be enabled by name. Registering the
+// same name twice panics.
+func Register(name string, factory CheckerFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("checker %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// Registered returns the names of all registered checkers
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// enabledCheckers builds the checkers named in cfg.Checker.Enabled together
+// with the ones switched on through the check_* flags
+func enabledCheckers(cfg config.AppConfig) ([]Checker, error) {
+	flags := []struct {
+		enabled bool
+		names   []string
+	}{
+		{cfg.Checker.CheckPods, []string{"pods"}},
+		{cfg.Checker.CheckNodes, []string{"nodes"}},
+		{cfg.Checker.CheckDeployments, []string{"deployments"}},
+		{cfg.Checker.CheckDaemonSets, []string{"daemonsets"}},
+		{cfg.Checker.CheckJobs, []string{"jobs"}},
+		{cfg.Checker.CheckCronJobs, []string{"cronjobs"}},
+		{cfg.Checker.CheckServices, []string{"services"}},
+		{cfg.Checker.CheckStorage, []string{"persistentvolumeclaims", "persistentvolumes"}},
+		{cfg.Checker.CheckEvents, []string{"events"}},
+		{cfg.Checker.CheckHPAs, []string{"hpas"}},
+		{cfg.Checker.CheckQuotas, []string{"quotas"}},
+		{cfg.Checker.CheckPDBs, []string{"pdbs"}},
+		{cfg.Checker.CheckCertificates, []string{"certificates"}},
+		{cfg.Checker.CheckIngresses, []string{"ingresses"}},
+	}
+
+	names := append([]string{}, cfg.Checker.Enabled...)
+	for _, flag := range flags {
+		if flag.enabled {
+			names = append(names, flag.names...)
+		}
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	seen := make(map[string]bool)
+	var checkers []Checker
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		factory, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown checker %q", name)
+		}
+		checkers = append(checkers, factory(cfg))
+	}
+
+	for _, rule := range cfg.Checker.CustomResources {
+		if rule.Level != "" && !types.ValidLevel(rule.Level) {
+			return nil, fmt.Errorf("custom resource %s: invalid level %q, must be critical, error, warning or info",
+				customResourceGVR(rule).GroupResource(), rule.Level)
+		}
+		checkers = append(checkers, &customResourceChecker{rule: rule})
+	}
+
+	return checkers, nil
+}
@@ -0,0 +1,153 @@
+package checker
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+// startChecker sets up the informers of c against client and waits for them
+// to sync, so c can be evaluated on its own
+func startChecker(ctx context.Context, t *testing.T, c Checker, client kubernetes.Interface) {
+	t.Helper()
+
+	hc := NewHealthChecker(ctx, client, config.AppConfig{}, nil)
+	if _, err := c.Informer(hc); err != nil {
+		t.Fatalf("Informer failed: %v", err)
+	}
+
+	hc.factory.Start(ctx.Done())
+	hc.factory.WaitForCacheSync(ctx.Done())
+	if hc.secretFactory != nil {
+		hc.secretFactory.Start(ctx.Done())
+		hc.secretFactory.WaitForCacheSync(ctx.Done())
+	}
+}
+
+type namespaceChecker struct{}
+
+func (c *namespaceChecker) Name() string     { return "test-namespaces" }
+func (c *namespaceChecker) Resource() string { return "namespace" }
+
+func (c *namespaceChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	return src.Factory().Core().V1().Namespaces().Informer(), nil
+}
+
+func (c *namespaceChecker) Evaluate(obj interface{}) []types.Alert {
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok || ns.Status.Phase != corev1.NamespaceTerminating {
+		return nil
+	}
+	return []types.Alert{{
+		Level:    types.AlertLevelWarning,
+		Resource: c.Resource(),
+		Name:     ns.Name,
+		Message:  "Namespace is terminating",
+	}}
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-namespaces", func(cfg config.AppConfig) Checker { return &namespaceChecker{} })
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "test-namespaces")
+		registryMu.Unlock()
+	}()
+
+	found := false
+	for _, name := range Registered() {
+		if name == "test-namespaces" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected test-namespaces in %v", Registered())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a name twice to panic")
+		}
+	}()
+	Register("test-namespaces", func(cfg config.AppConfig) Checker { return &namespaceChecker{} })
+}
+
+func TestEnabledCheckers(t *testing.T) {
+	cfg := config.AppConfig{}
+	cfg.Checker.CheckPods = true
+	cfg.Checker.CheckStorage = true
+	cfg.Checker.Enabled = []string{"nodes", "pods"}
+	cfg.Checker.CustomResources = []config.CustomResourceCheck{
+		{Group: "cert-manager.io", Version: "v1", Resource: "certificates", ConditionType: "Ready"},
+	}
+
+	checkers, err := enabledCheckers(cfg)
+	if err != nil {
+		t.Fatalf("enabledCheckers failed: %v", err)
+	}
+
+	var names []string
+	for _, c := range checkers {
+		names = append(names, c.Name())
+	}
+	expected := []string{"nodes", "pods", "persistentvolumeclaims", "persistentvolumes", "certificates.cert-manager.io"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected checkers %v, got %v", expected, names)
+	}
+
+	cfg.Checker.Enabled = []string{"nope"}
+	if _, err := enabledCheckers(cfg); err == nil {
+		t.Error("Expected an unknown checker to fail")
+	}
+
+	cfg.Checker.Enabled = nil
+	cfg.Checker.CustomResources[0].Level = "err"
+	if _, err := enabledCheckers(cfg); err == nil {
+		t.Error("Expected an invalid custom resource level to fail")
+	}
+}
+
+func TestHealthChecker_Start_RegisteredChecker(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	Register("test-namespaces", func(cfg config.AppConfig) Checker { return &namespaceChecker{} })
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "test-namespaces")
+		registryMu.Unlock()
+	}()
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "old"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	}
+	client := fake.NewSimpleClientset(ns)
+
+	cfg := config.AppConfig{}
+	cfg.Checker.Enabled = []string{"test-namespaces"}
+	notifier := &MockNotifier{}
+
+	hc := NewHealthChecker(ctx, client, cfg, notifier)
+	if err := hc.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// the handler runs on the informer's goroutine
+	deadline := time.Now().Add(2 * time.Second)
+	for len(notifier.GetAlerts()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(notifier.GetAlerts()) != 1 {
+		t.Errorf("Expected 1 alert, got %d", len(notifier.GetAlerts()))
+	}
+}
@@ -0,0 +1,91 @@
+package checker
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// IgnoreAnnotation set to "true" opts an object out of all checks
+const IgnoreAnnotation = "reliability-informer/ignore"
+
+// scope decides which objects are checked. Namespace patterns and the label
+// selector only apply to namespaced objects, since nodes and volumes do not
+// carry workload labels. Events are selected through their involved object
+// by the event checker.
+type scope struct {
+	include  []string
+	exclude  []string
+	selector labels.Selector
+}
+
+func newScope(cfg config.AppConfig) (*scope, error) {
+	s := &scope{
+		include:  cfg.Checker.Scope.IncludeNamespaces,
+		exclude:  cfg.Checker.Scope.ExcludeNamespaces,
+		selector: labels.Everything(),
+	}
+
+	for _, pattern := range append(append([]string{}, s.include...), s.exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid namespace pattern %q: %w", pattern, err)
+		}
+	}
+
+	if cfg.Checker.Scope.LabelSelector != "" {
+		selector, err := labels.Parse(cfg.Checker.Scope.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %w", cfg.Checker.Scope.LabelSelector, err)
+		}
+		s.selector = selector
+	}
+
+	return s, nil
+}
+
+func (s *scope) matches(obj interface{}) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return true
+	}
+
+	if strings.EqualFold(accessor.GetAnnotations()[IgnoreAnnotation], "true") {
+		return false
+	}
+	ns := accessor.GetNamespace()
+	if ns == "" {
+		return true
+	}
+	if !s.matchesNamespace(ns) {
+		return false
+	}
+	if _, ok := obj.(*corev1.Event); ok {
+		return true
+	}
+
+	return s.selector.Matches(labels.Set(accessor.GetLabels()))
+}
+
+// matchesNamespace reports whether ns matches an include pattern (or there
+// are none) and no exclude pattern
+func (s *scope) matchesNamespace(ns string) bool {
+	for _, pattern := range s.exclude {
+		if ok, _ := path.Match(pattern, ns); ok {
+			return false
+		}
+	}
+	if len(s.include) == 0 {
+		return true
+	}
+	for _, pattern := range s.include {
+		if ok, _ := path.Match(pattern, ns); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestScope_matches(t *testing.T) {
+	cfg := config.AppConfig{}
+	cfg.Checker.Scope.IncludeNamespaces = []string{"team-*", "default"}
+	cfg.Checker.Scope.ExcludeNamespaces = []string{"team-sandbox-*"}
+	cfg.Checker.Scope.LabelSelector = "tier!=dev"
+
+	s, err := newScope(cfg)
+	if err != nil {
+		t.Fatalf("newScope failed: %v", err)
+	}
+
+	newPod := func(namespace string, labels, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:        "api",
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		obj      interface{}
+		expected bool
+	}{
+		{name: "Included namespace", obj: newPod("default", nil, nil), expected: true},
+		{name: "Included by glob", obj: newPod("team-payments", nil, nil), expected: true},
+		{name: "Not included", obj: newPod("kube-system", nil, nil), expected: false},
+		{name: "Excluded by glob", obj: newPod("team-sandbox-1", nil, nil), expected: false},
+		{name: "Label not selected", obj: newPod("default", map[string]string{"tier": "dev"}, nil), expected: false},
+		{name: "Ignore annotation", obj: newPod("default", nil, map[string]string{IgnoreAnnotation: "true"}), expected: false},
+		{name: "Ignore annotation false", obj: newPod("default", nil, map[string]string{IgnoreAnnotation: "false"}), expected: true},
+		{name: "Cluster scoped object", obj: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "rpi-1"}}, expected: true},
+		{
+			name:     "Selector does not apply to nodes",
+			obj:      &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "rpi-1", Labels: map[string]string{"tier": "dev"}}},
+			expected: true,
+		},
+		{
+			name:     "Ignored node",
+			obj:      &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "rpi-1", Annotations: map[string]string{IgnoreAnnotation: "true"}}},
+			expected: false,
+		},
+		{
+			name:     "Selector does not apply to events",
+			obj:      &corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "api.17f", Namespace: "default", Labels: map[string]string{"tier": "dev"}}},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.matches(tt.obj); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewScope_Invalid(t *testing.T) {
+	cfg := config.AppConfig{}
+	cfg.Checker.Scope.IncludeNamespaces = []string{"team-["}
+	if _, err := newScope(cfg); err == nil {
+		t.Error("Expected an invalid pattern to fail")
+	}
+
+	cfg = config.AppConfig{}
+	cfg.Checker.Scope.LabelSelector = "tier in"
+	if _, err := newScope(cfg); err == nil {
+		t.Error("Expected an invalid label selector to fail")
+	}
+}
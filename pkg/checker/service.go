@@ -0,0 +1,101 @@
+package checker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const defaultServiceGracePeriod = 2 * time.Minute
+
+func init() {
+	Register("services", func(cfg config.AppConfig) Checker {
+		grace := cfg.Checker.Services.GracePeriod
+		if grace <= 0 {
+			grace = defaultServiceGracePeriod
+		}
+		return &serviceChecker{grace: grace}
+	})
+}
+
+type serviceChecker struct {
+	grace     time.Duration
+	slices    discoverylisters.EndpointSliceLister
+	unhealthy graceTracker
+}
+
+func (c *serviceChecker) Name() string     { return "services" }
+func (c *serviceChecker) Resource() string { return types.ResourceTypeService }
+
+func (c *serviceChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	c.slices = src.Factory().Discovery().V1().EndpointSlices().Lister()
+
+	return src.Factory().Core().V1().Services().Informer(), nil
+}
+
+func (c *serviceChecker) Evaluate(obj interface{}) []types.Alert {
+	svc, ok := obj.(*corev1.Service)
+	// without a selector endpoints are managed by hand, nothing to compare against
+	if !ok || len(svc.Spec.Selector) == 0 || svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return nil
+	}
+	if c.slices == nil {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s/%s", svc.Namespace, svc.Name)
+
+	ready, err := readyEndpoints(c.slices, svc.Namespace, svc.Name)
+	if err != nil {
+		fmt.Printf("Failed to list endpoint slices for %s: %v\n", name, err)
+		return nil
+	}
+	if ready > 0 {
+		c.unhealthy.clear(name)
+		return nil
+	}
+
+	// no ready endpoints
+	if down := c.unhealthy.unhealthyFor(name); down >= c.grace {
+		return []types.Alert{{
+			Level:    types.AlertLevelError,
+			Resource: types.ResourceTypeService,
+			Name:     name,
+			Message:  fmt.Sprintf("Service has no ready endpoints for %s", down.Round(time.Second)),
+		}}
+	}
+	return nil
+}
+
+func (c *serviceChecker) Forget(obj interface{}) {
+	if svc, ok := obj.(*corev1.Service); ok {
+		c.unhealthy.clear(fmt.Sprintf("%s/%s", svc.Namespace, svc.Name))
+	}
+}
+
+// readyEndpoints counts the ready endpoints across all slices of a service
+func readyEndpoints(lister discoverylisters.EndpointSliceLister, namespace, service string) (int, error) {
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: service})
+	slices, err := lister.EndpointSlices(namespace).List(selector)
+	if err != nil {
+		return 0, err
+	}
+
+	ready := 0
+	for _, slice := range slices {
+		for _, ep := range slice.Endpoints {
+			// nil means unknown, which consumers should treat as ready
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				ready++
+			}
+		}
+	}
+	return ready, nil
+}
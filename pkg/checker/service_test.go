@@ -0,0 +1,105 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newEndpointSlice(service string, ready ...bool) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      service + "-abcde",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	for _, r := range ready {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{"10.42.0.10"},
+			Conditions: discoveryv1.EndpointConditions{Ready: &r},
+		})
+	}
+	return slice
+}
+
+func TestServiceChecker_Evaluate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	selector := map[string]string{"app": "api"}
+	services := []*corev1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Selector: selector},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "healthy", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Selector: selector},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "headless-manual", Namespace: "default"},
+		},
+	}
+
+	client := fake.NewSimpleClientset(
+		services[0], services[1], services[2],
+		newEndpointSlice("broken", false, false),
+		newEndpointSlice("healthy", false, true),
+	)
+	c := &serviceChecker{grace: time.Minute}
+	startChecker(ctx, t, c, client)
+
+	tests := []struct {
+		name      string
+		svc       *corev1.Service
+		downSince time.Duration
+		expected  int
+	}{
+		{
+			name:      "No ready endpoints within grace period",
+			svc:       services[0],
+			downSince: 0,
+			expected:  0,
+		},
+		{
+			name:      "No ready endpoints past grace period",
+			svc:       services[0],
+			downSince: 5 * time.Minute,
+			expected:  1,
+		},
+		{
+			name:      "Some ready endpoints",
+			svc:       services[1],
+			downSince: 5 * time.Minute,
+			expected:  0,
+		},
+		{
+			name:      "Service without selector",
+			svc:       services[2],
+			downSince: 5 * time.Minute,
+			expected:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.unhealthy.setSince("default/"+tt.svc.Name, time.Now().Add(-tt.downSince))
+
+			alerts := c.Evaluate(tt.svc)
+
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+			}
+		})
+	}
+}
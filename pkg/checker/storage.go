@@ -0,0 +1,259 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const defaultPVCPendingGracePeriod = 5 * time.Minute
+
+const (
+	// set by the scheduler once a pod using a WaitForFirstConsumer claim is placed
+	annSelectedNode = "volume.kubernetes.io/selected-node"
+	// marks the storage class used by claims without one
+	annDefaultStorageClass = "storageclass.kubernetes.io/is-default-class"
+)
+
+func init() {
+	Register("persistentvolumeclaims", func(cfg config.AppConfig) Checker {
+		grace := cfg.Checker.Storage.PendingGracePeriod
+		if grace <= 0 {
+			grace = defaultPVCPendingGracePeriod
+		}
+		return &pvcChecker{pendingGrace: grace}
+	})
+	Register("persistentvolumes", func(cfg config.AppConfig) Checker { return &pvChecker{} })
+}
+
+type pvcChecker struct {
+	pendingGrace time.Duration
+	volumes      corelisters.PersistentVolumeLister
+	nodes        corelisters.NodeLister
+	classes      storagelisters.StorageClassLister
+}
+
+func (c *pvcChecker) Name() string     { return "persistentvolumeclaims" }
+func (c *pvcChecker) Resource() string { return types.ResourceTypePVC }
+
+func (c *pvcChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	// volume and node listers are used to find claims bound to volumes on down nodes
+	c.volumes = src.Factory().Core().V1().PersistentVolumes().Lister()
+	c.nodes = src.Factory().Core().V1().Nodes().Lister()
+	// claims of WaitForFirstConsumer classes are pending until a pod uses them
+	c.classes = src.Factory().Storage().V1().StorageClasses().Lister()
+
+	return src.Factory().Core().V1().PersistentVolumeClaims().Informer(), nil
+}
+
+func (c *pvcChecker) Evaluate(obj interface{}) []types.Alert {
+	pvc, ok := obj.(*corev1.PersistentVolumeClaim)
+	if !ok {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s/%s", pvc.Namespace, pvc.Name)
+
+	// stuck pending, e.g. the local-path provisioner failed
+	if pvc.Status.Phase == corev1.ClaimPending {
+		if c.waitingForConsumer(pvc) {
+			return nil
+		}
+		pending := time.Since(pvc.CreationTimestamp.Time)
+		if pending >= c.pendingGrace {
+			return []types.Alert{{
+				Level:    types.AlertLevelError,
+				Resource: types.ResourceTypePVC,
+				Name:     name,
+				Message:  fmt.Sprintf("Claim pending for %s", pending.Round(time.Second)),
+			}}
+		}
+		return nil
+	}
+
+	// lost its volume
+	if pvc.Status.Phase == corev1.ClaimLost {
+		return []types.Alert{{
+			Level:    types.AlertLevelError,
+			Resource: types.ResourceTypePVC,
+			Name:     name,
+			Message:  fmt.Sprintf("Claim lost its volume %s", pvc.Spec.VolumeName),
+		}}
+	}
+
+	// bound to a node local volume on a node that is down
+	if pvc.Status.Phase == corev1.ClaimBound && pvc.Spec.VolumeName != "" {
+		if notReady := c.notReadyVolumeNodes(pvc.Spec.VolumeName); len(notReady) > 0 {
+			alert := types.Alert{
+				Level:    types.AlertLevelError,
+				Resource: types.ResourceTypePVC,
+				Name:     name,
+				Message: fmt.Sprintf("Volume %s is on not ready node: %s",
+					pvc.Spec.VolumeName, strings.Join(notReady, ", ")),
+			}
+			if len(notReady) == 1 {
+				alert.Labels = map[string]string{types.LabelNode: notReady[0]}
+			}
+			return []types.Alert{alert}
+		}
+	}
+
+	return nil
+}
+
+type pvChecker struct{}
+
+func (c *pvChecker) Name() string     { return "persistentvolumes" }
+func (c *pvChecker) Resource() string { return types.ResourceTypePV }
+
+func (c *pvChecker) Informer(src InformerSource) (cache.SharedIndexInformer, error) {
+	return src.Factory().Core().V1().PersistentVolumes().Informer(), nil
+}
+
+func (c *pvChecker) Evaluate(obj interface{}) []types.Alert {
+	pv, ok := obj.(*corev1.PersistentVolume)
+	if !ok {
+		return nil
+	}
+
+	switch pv.Status.Phase {
+	case corev1.VolumeFailed:
+		return []types.Alert{{
+			Level:    types.AlertLevelError,
+			Resource: types.ResourceTypePV,
+			Name:     pv.Name,
+			Message:  fmt.Sprintf("Volume failed: %s", pv.Status.Message),
+		}}
+	case corev1.VolumeReleased:
+		return []types.Alert{{
+			Level:    types.AlertLevelWarning,
+			Resource: types.ResourceTypePV,
+			Name:     pv.Name,
+			Message:  fmt.Sprintf("Volume released by its claim, reclaim policy %s", pv.Spec.PersistentVolumeReclaimPolicy),
+		}}
+	}
+	return nil
+}
+
+// waitingForConsumer reports whether a claim is pending because its storage
+// class binds it only once a pod using it has been scheduled
+func (c *pvcChecker) waitingForConsumer(pvc *corev1.PersistentVolumeClaim) bool {
+	if c.classes == nil || pvc.Annotations[annSelectedNode] != "" {
+		return false
+	}
+
+	class, err := c.storageClass(pvc)
+	if err != nil || class == nil {
+		return false
+	}
+	return class.VolumeBindingMode != nil && *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer
+}
+
+// storageClass returns the class of a claim, the default class when it names
+// none, or nil when it has none
+func (c *pvcChecker) storageClass(pvc *corev1.PersistentVolumeClaim) (*storagev1.StorageClass, error) {
+	if pvc.Spec.StorageClassName != nil {
+		if *pvc.Spec.StorageClassName == "" {
+			return nil, nil
+		}
+		return c.classes.Get(*pvc.Spec.StorageClassName)
+	}
+
+	classes, err := c.classes.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, class := range classes {
+		if class.Annotations[annDefaultStorageClass] == "true" {
+			return class, nil
+		}
+	}
+	return nil, nil
+}
+
+// notReadyVolumeNodes returns the not ready nodes a volume is pinned to
+// through its required node affinity
+func (c *pvcChecker) notReadyVolumeNodes(volumeName string) []string {
+	if c.volumes == nil || c.nodes == nil {
+		return nil
+	}
+
+	pv, err := c.volumes.Get(volumeName)
+	if err != nil || pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return nil
+	}
+
+	nodes, err := c.nodes.List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+
+	var notReady []string
+	for _, node := range nodes {
+		if !matchesNodeSelector(pv.Spec.NodeAffinity.Required, node) || isNodeReady(node) {
+			continue
+		}
+		notReady = append(notReady, node.Name)
+	}
+	sort.Strings(notReady)
+
+	return notReady
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// matchesNodeSelector reports whether the node labels match any of the terms.
+// Only match expressions are evaluated, Gt and Lt never match.
+func matchesNodeSelector(ns *corev1.NodeSelector, node *corev1.Node) bool {
+	operators := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	}
+
+	for _, term := range ns.NodeSelectorTerms {
+		if len(term.MatchExpressions) == 0 {
+			continue
+		}
+
+		selector := labels.NewSelector()
+		valid := true
+		for _, expr := range term.MatchExpressions {
+			op, ok := operators[expr.Operator]
+			if !ok {
+				valid = false
+				break
+			}
+			req, err := labels.NewRequirement(expr.Key, op, expr.Values)
+			if err != nil {
+				valid = false
+				break
+			}
+			selector = selector.Add(*req)
+		}
+
+		if valid && selector.Matches(labels.Set(node.Labels)) {
+			return true
+		}
+	}
+	return false
+}
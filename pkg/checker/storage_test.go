@@ -0,0 +1,211 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPVCChecker_Evaluate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	localPV := func(name, node string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				NodeAffinity: &corev1.VolumeNodeAffinity{
+					Required: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{
+								MatchExpressions: []corev1.NodeSelectorRequirement{
+									{
+										Key:      "kubernetes.io/hostname",
+										Operator: corev1.NodeSelectorOpIn,
+										Values:   []string{node},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	node := func(name string, ready corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"kubernetes.io/hostname": name},
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+			},
+		}
+	}
+
+	waitForConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	immediate := storagev1.VolumeBindingImmediate
+	localPath, nfs := "local-path", "nfs"
+
+	client := fake.NewSimpleClientset(
+		&storagev1.StorageClass{
+			ObjectMeta:        metav1.ObjectMeta{Name: localPath, Annotations: map[string]string{annDefaultStorageClass: "true"}},
+			Provisioner:       "rancher.io/local-path",
+			VolumeBindingMode: &waitForConsumer,
+		},
+		&storagev1.StorageClass{
+			ObjectMeta:        metav1.ObjectMeta{Name: nfs},
+			Provisioner:       "nfs.csi.k8s.io",
+			VolumeBindingMode: &immediate,
+		},
+		localPV("pv-down", "rpi-1"),
+		localPV("pv-up", "rpi-2"),
+		node("rpi-1", corev1.ConditionUnknown),
+		node("rpi-2", corev1.ConditionTrue),
+	)
+	c := &pvcChecker{pendingGrace: time.Minute}
+	startChecker(ctx, t, c, client)
+
+	old := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	now := metav1.NewTime(time.Now())
+
+	tests := []struct {
+		name     string
+		pvc      *corev1.PersistentVolumeClaim
+		expected int
+	}{
+		{
+			name: "Claim stuck pending",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", CreationTimestamp: old},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &nfs},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			},
+			expected: 1,
+		},
+		{
+			name: "Claim waiting for its first consumer",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", CreationTimestamp: old},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &localPath},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			},
+			expected: 0,
+		},
+		{
+			name: "Claim of the default class waiting for its first consumer",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", CreationTimestamp: old},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			},
+			expected: 0,
+		},
+		{
+			name: "Claim stuck pending after its consumer was scheduled",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "data",
+					Namespace:         "default",
+					CreationTimestamp: old,
+					Annotations:       map[string]string{annSelectedNode: "rpi-2"},
+				},
+				Spec:   corev1.PersistentVolumeClaimSpec{StorageClassName: &localPath},
+				Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			},
+			expected: 1,
+		},
+		{
+			name: "Claim just created",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", CreationTimestamp: now},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			},
+			expected: 0,
+		},
+		{
+			name: "Claim bound to volume on not ready node",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", CreationTimestamp: old},
+				Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-down"},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+			},
+			expected: 1,
+		},
+		{
+			name: "Claim bound to volume on ready node",
+			pvc: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default", CreationTimestamp: old},
+				Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-up"},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := c.Evaluate(tt.pvc)
+
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+			}
+		})
+	}
+}
+
+func TestPVChecker_Evaluate(t *testing.T) {
+	c := &pvChecker{}
+
+	tests := []struct {
+		name     string
+		pv       *corev1.PersistentVolume
+		expected int
+	}{
+		{
+			name: "Failed volume",
+			pv: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+				Status:     corev1.PersistentVolumeStatus{Phase: corev1.VolumeFailed, Message: "recycle failed"},
+			},
+			expected: 1,
+		},
+		{
+			name: "Released volume",
+			pv: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv-2"},
+				Status:     corev1.PersistentVolumeStatus{Phase: corev1.VolumeReleased},
+			},
+			expected: 1,
+		},
+		{
+			name: "Bound volume",
+			pv: &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv-3"},
+				Status:     corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := c.Evaluate(tt.pv)
+
+			if len(alerts) != tt.expected {
+				t.Errorf("Expected %d alerts, got %d", tt.expected, len(alerts))
+				for i, alert := range alerts {
+					t.Logf("Alert %d: %+v", i, alert)
+				}
+			}
+		})
+	}
+}
@@ -1,28 +1,236 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"time"
 
+	"github.com/5iing/k8s-reliablity-informer/pkg/message"
 	"gopkg.in/yaml.v3"
 )
 
 type AppConfig struct {
+	// name of the cluster, added to every alert
+	Cluster string `yaml:"cluster"`
+
 	Checker struct {
-		CheckPods        bool `yaml:"check_pods"`
-		CheckNodes       bool `yaml:"check_nodes"`
-		CheckDeployments bool `yaml:"check_deployments"`
+		CheckPods         bool `yaml:"check_pods"`
+		CheckNodes        bool `yaml:"check_nodes"`
+		CheckDeployments  bool `yaml:"check_deployments"`
+		CheckDaemonSets   bool `yaml:"check_daemonsets"`
+		CheckJobs         bool `yaml:"check_jobs"`
+		CheckCronJobs     bool `yaml:"check_cronjobs"`
+		CheckServices     bool `yaml:"check_services"`
+		CheckStorage      bool `yaml:"check_storage"`
+		CheckEvents       bool `yaml:"check_events"`
+		CheckHPAs         bool `yaml:"check_hpas"`
+		CheckQuotas       bool `yaml:"check_quotas"`
+		CheckPDBs         bool `yaml:"check_pdbs"`
+		CheckCertificates bool `yaml:"check_certificates"`
+		CheckIngresses    bool `yaml:"check_ingresses"`
+
+		// checkers to run by registered name, in addition to the check_* flags
+		Enabled []string `yaml:"enabled"`
+
+		// which objects are checked at all, applied to every check
+		Scope struct {
+			// glob patterns such as "team-*", no includes means all namespaces
+			IncludeNamespaces []string `yaml:"include_namespaces"`
+			ExcludeNamespaces []string `yaml:"exclude_namespaces"`
+			// e.g. "tier!=dev", namespaced objects whose labels do not
+			// match are skipped. Nodes and volumes are always checked,
+			// events are matched by the labels of their involved object.
+			LabelSelector string `yaml:"label_selector"`
+		} `yaml:"scope"`
+
+		Jobs struct {
+			// 0 disables the long running job check
+			MaxDuration time.Duration `yaml:"max_duration"`
+		} `yaml:"jobs"`
+
+		CronJobs struct {
+			// schedules missed since the last success before alerting, default 1
+			MissedSchedules int `yaml:"missed_schedules"`
+		} `yaml:"cronjobs"`
+
+		Services struct {
+			// how long a service may have no ready endpoints, default 2m
+			GracePeriod time.Duration `yaml:"grace_period"`
+		} `yaml:"services"`
+
+		Storage struct {
+			// how long a claim may stay Pending, default 5m
+			PendingGracePeriod time.Duration `yaml:"pending_grace_period"`
+		} `yaml:"storage"`
+
+		// Warning events are forwarded when they pass the include lists
+		// (empty means everything) and none of the exclude lists
+		Events struct {
+			IncludeReasons    []string `yaml:"include_reasons"`
+			ExcludeReasons    []string `yaml:"exclude_reasons"`
+			IncludeKinds      []string `yaml:"include_kinds"`
+			ExcludeKinds      []string `yaml:"exclude_kinds"`
+			IncludeNamespaces []string `yaml:"include_namespaces"`
+			ExcludeNamespaces []string `yaml:"exclude_namespaces"`
+			// times an event must have occurred before it is forwarded, default 1
+			MinCount int32 `yaml:"min_count"`
+		} `yaml:"events"`
+
+		HPAs struct {
+			// how long an autoscaler may sit at max replicas, default 15m
+			MaxReplicasDuration time.Duration `yaml:"max_replicas_duration"`
+		} `yaml:"hpas"`
+
+		// percent of a hard limit in use before alerting, default 80 and 95
+		Quotas struct {
+			WarningPercent float64 `yaml:"warning_percent"`
+			ErrorPercent   float64 `yaml:"error_percent"`
+		} `yaml:"quotas"`
+
+		PDBs struct {
+			// how long a budget may be violated or block disruptions, default 10m
+			GracePeriod time.Duration `yaml:"grace_period"`
+		} `yaml:"pdbs"`
+
+		// days before expiry to alert at, default 30 and 7
+		Certificates struct {
+			WarningDays int `yaml:"warning_days"`
+			ErrorDays   int `yaml:"error_days"`
+		} `yaml:"certificates"`
+
+		CustomResources []CustomResourceCheck `yaml:"custom_resources"`
+		CELRules        []CELRule             `yaml:"cel_rules"`
 	} `yaml:"checker"`
 
 	Notifiers struct {
 		Discord struct {
 			Enabled    bool   `yaml:"enabled"`
 			WebhookURL string `yaml:"webhook_url"`
+			// webhook URLs by the route objects name in their route annotation
+			Routes map[string]string `yaml:"routes"`
+			// also used for the routes
+			Templates MessageTemplates `yaml:"templates"`
 		} `yaml:"discord"`
 
 		Console struct {
-			Enabled bool `yaml:"enabled"`
+			Enabled   bool             `yaml:"enabled"`
+			Templates MessageTemplates `yaml:"templates"`
 		} `yaml:"console"`
+
+		// also tell when an alert stops firing, acknowledged alerts always do
+		SendResolved bool `yaml:"send_resolved"`
 	} `yaml:"notifiers"`
+
+	// alerts with the same values for the By labels are batched into one
+	// notification, each alert is sent on its own when By is empty
+	Grouping struct {
+		By []string `yaml:"by"`
+		// how long the first alert of a group waits for others, default 30s
+		GroupWait time.Duration `yaml:"group_wait"`
+		// how often alerts added to a group later are sent, default 5m
+		GroupInterval time.Duration `yaml:"group_interval"`
+	} `yaml:"grouping"`
+
+	InhibitRules       []InhibitRule       `yaml:"inhibit_rules"`
+	MaintenanceWindows []MaintenanceWindow `yaml:"maintenance_windows"`
+	Escalations        []EscalationPolicy  `yaml:"escalations"`
+
+	Silences struct {
+		// file the silences are saved to, empty keeps them in memory only
+		Path string `yaml:"path"`
+	} `yaml:"silences"`
+
+	// HTTP API used by the silence command, disabled without an address
+	API struct {
+		Listen string `yaml:"listen"`
+		// bearer token required on every request, falls back to the
+		// APITokenEnv environment variable
+		Token string `yaml:"token"`
+	} `yaml:"api"`
+}
+
+// APITokenEnv holds the API token when the config does not, both for the
+// checker and the commands calling its API
+const APITokenEnv = "RELIABILITY_INFORMER_API_TOKEN"
+
+// CustomResourceCheck alerts when an object of the resource has a status
+// condition of ConditionType in one of the unhealthy statuses
+type CustomResourceCheck struct {
+	Group         string `yaml:"group"`
+	Version       string `yaml:"version"`
+	Resource      string `yaml:"resource"`
+	ConditionType string `yaml:"condition_type"`
+	// default ["False"]
+	UnhealthyStatuses []string `yaml:"unhealthy_statuses"`
+	// default warning
+	Level string `yaml:"level"`
+}
+
+// CELRule alerts when Expression evaluates to true for an object of Resource.
+// Resource is a checker resource type such as "deployment", or the
+// resource.group of a custom resource, e.g. "certificates.cert-manager.io".
+// Message is a text/template executed with .object.
+type CELRule struct {
+	Name       string `yaml:"name"`
+	Resource   string `yaml:"resource"`
+	Expression string `yaml:"expression"`
+	// default warning
+	Level   string `yaml:"level"`
+	Message string `yaml:"message"`
+}
+
+// InhibitRule suppresses alerts matching TargetMatchers while an alert
+// matching SourceMatchers is firing with the same values for the Equal labels.
+// Matchers are written like `resource="node"` or `level=~"critical|error"`
+// and match the level, resource and name of an alert as well as its labels.
+type InhibitRule struct {
+	SourceMatchers []string `yaml:"source_matchers"`
+	TargetMatchers []string `yaml:"target_matchers"`
+	Equal          []string `yaml:"equal"`
+}
+
+// MaintenanceWindow suppresses the alerts matching Matchers for Duration
+// after every start of Schedule, or with Action "downgrade" sends them as info.
+// Schedule is a standard 5 field cron expression evaluated in Timezone.
+type MaintenanceWindow struct {
+	Name     string        `yaml:"name"`
+	Schedule string        `yaml:"schedule"`
+	Duration time.Duration `yaml:"duration"`
+	// IANA name such as "Europe/Berlin", default local time
+	Timezone string   `yaml:"timezone"`
+	Matchers []string `yaml:"matchers"`
+	// suppress (default) or downgrade
+	Action string `yaml:"action"`
+}
+
+// EscalationPolicy notifies the receiver of each step once an alert matching
+// Matchers has been firing for After. Receivers name a notifier route, e.g.
+// a key of notifiers.discord.routes.
+type EscalationPolicy struct {
+	Name     string           `yaml:"name"`
+	Matchers []string         `yaml:"matchers"`
+	Steps    []EscalationStep `yaml:"steps"`
+}
+
+type EscalationStep struct {
+	After    time.Duration `yaml:"after"`
+	Receiver string        `yaml:"receiver"`
+}
+
+// MessageTemplates format the notifications of a notifier as the title
+// followed by the body. They are text/template templates executed with
+// message.Data, the resolved ones once an alert stopped firing. Without any
+// the built in format is used.
+type MessageTemplates struct {
+	Title         string `yaml:"title"`
+	Body          string `yaml:"body"`
+	ResolvedTitle string `yaml:"resolved_title"`
+	ResolvedBody  string `yaml:"resolved_body"`
+}
+
+// Compile parses the templates, nil when none are set
+func (t MessageTemplates) Compile() (*message.Templates, error) {
+	return message.New(t.Title, t.Body, t.ResolvedTitle, t.ResolvedBody)
 }
 
 func LoadConfig(path string) (*AppConfig, error) {
@@ -32,6 +240,19 @@ func LoadConfig(path string) (*AppConfig, error) {
 	}
 
 	var config AppConfig
-	err = yaml.Unmarshal(data, &config)
-	return &config, err
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return &config, err
+	}
+
+	if config.API.Token == "" {
+		config.API.Token = os.Getenv(APITokenEnv)
+	}
+
+	if _, err := config.Notifiers.Discord.Templates.Compile(); err != nil {
+		return nil, fmt.Errorf("notifiers.discord.templates: %w", err)
+	}
+	if _, err := config.Notifiers.Console.Templates.Compile(); err != nil {
+		return nil, fmt.Errorf("notifiers.console.templates: %w", err)
+	}
+	return &config, nil
 }
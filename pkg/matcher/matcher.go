@@ -0,0 +1,91 @@
+// Package matcher parses and evaluates the label matchers used by inhibit
+// rules and silences
+package matcher
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+// Matcher tests one label of an alert, written like `namespace="apps"`.
+// The operators are =, !=, =~ and !~, regular expressions are anchored.
+type Matcher struct {
+	name  string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+var pattern = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_./-]*)\s*(=~|!~|!=|=)\s*(.*?)\s*$`)
+
+// Parse parses a single matcher
+func Parse(s string) (Matcher, error) {
+	parts := pattern.FindStringSubmatch(s)
+	if parts == nil {
+		return Matcher{}, fmt.Errorf("invalid matcher %q", s)
+	}
+
+	m := Matcher{name: parts[1], op: parts[2], value: parts[3]}
+	if strings.HasPrefix(m.value, `"`) {
+		value, err := strconv.Unquote(m.value)
+		if err != nil {
+			return Matcher{}, fmt.Errorf("invalid matcher %q: %w", s, err)
+		}
+		m.value = value
+	}
+
+	if m.op == "=~" || m.op == "!~" {
+		re, err := regexp.Compile("^(?:" + m.value + ")$")
+		if err != nil {
+			return Matcher{}, fmt.Errorf("invalid matcher %q: %w", s, err)
+		}
+		m.re = re
+	}
+	return m, nil
+}
+
+// ParseAll parses a list of matchers, failing on the first invalid one
+func ParseAll(list []string) ([]Matcher, error) {
+	matchers := make([]Matcher, 0, len(list))
+	for _, s := range list {
+		m, err := Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// Matches reports whether the label of alert matches
+func (m Matcher) Matches(alert types.Alert) bool {
+	value := alert.Label(m.name)
+	switch m.op {
+	case "=":
+		return value == m.value
+	case "!=":
+		return value != m.value
+	case "=~":
+		return m.re.MatchString(value)
+	default:
+		return !m.re.MatchString(value)
+	}
+}
+
+func (m Matcher) String() string {
+	return fmt.Sprintf("%s%s%q", m.name, m.op, m.value)
+}
+
+// MatchesAll reports whether alert matches every matcher
+func MatchesAll(matchers []Matcher, alert types.Alert) bool {
+	for _, m := range matchers {
+		if !m.Matches(alert) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+func TestParseMatcher(t *testing.T) {
+	alert := types.Alert{
+		Level:    types.AlertLevelCritical,
+		Resource: types.ResourceTypeNode,
+		Name:     "rpi-1",
+		Labels:   map[string]string{types.LabelNode: "rpi-1"},
+	}
+
+	tests := []struct {
+		matcher  string
+		expected bool
+		invalid  bool
+	}{
+		{matcher: `resource="node"`, expected: true},
+		{matcher: `resource = node`, expected: true},
+		{matcher: `level!="warning"`, expected: true},
+		{matcher: `level=~"critical|error"`, expected: true},
+		{matcher: `node=~"rpi-.*"`, expected: true},
+		{matcher: `name!~"rpi"`, expected: true},
+		{matcher: `namespace="apps"`, expected: false},
+		{matcher: `node=~"rpi-("`, invalid: true},
+		{matcher: `just a name`, invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.matcher, func(t *testing.T) {
+			m, err := Parse(tt.matcher)
+			if tt.invalid {
+				if err == nil {
+					t.Errorf("Expected %q to be invalid", tt.matcher)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if got := m.Matches(alert); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Package message formats the notifications sent for alerts, either in the
+// built in format or with text/template templates from the config
+package message
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Data is what templates are executed with: the alert fields such as
+// .Level, .Name, .Labels and .StartsAt, and the state of the alert
+type Data struct {
+	types.Alert
+	// firing or resolved
+	Status string
+	// who acknowledged the alert, if anyone
+	AckedBy    string
+	AckComment string
+}
+
+// Emoji returns the emoji of the alert level
+func (d *Data) Emoji() string {
+	return d.GetEmoji()
+}
+
+// Resolved reports whether the alert stopped firing
+func (d *Data) Resolved() bool {
+	return d.Status == StatusResolved
+}
+
+// Duration returns how long the alert fired, up to now while it is firing
+func (d *Data) Duration() time.Duration {
+	if d.StartsAt.IsZero() {
+		return 0
+	}
+	if d.EndsAt.IsZero() {
+		return time.Since(d.StartsAt)
+	}
+	return d.EndsAt.Sub(d.StartsAt)
+}
+
+// Default formats d in the built in format
+func Default(d *Data) string {
+	if !d.Resolved() {
+		return d.FormatMessage()
+	}
+
+	msg := fmt.Sprintf("✅ [%s] %s: Resolved after %s", d.Resource, d.Name, d.Duration().Round(time.Minute))
+	if d.AckedBy != "" {
+		msg += fmt.Sprintf(", acknowledged by %s", d.AckedBy)
+		if d.AckComment != "" {
+			msg += fmt.Sprintf(" (%s)", d.AckComment)
+		}
+	}
+	return msg
+}
+
+var funcs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join": func(sep string, elems []string) string {
+		return strings.Join(elems, sep)
+	},
+	"truncate": func(n int, s string) string {
+		if r := []rune(s); len(r) > n {
+			return string(r[:n]) + "…"
+		}
+		return s
+	},
+	"since": func(t time.Time) time.Duration {
+		return time.Since(t).Round(time.Second)
+	},
+	"round": func(d time.Duration) time.Duration {
+		return d.Round(time.Second)
+	},
+	"timeFormat": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"default": func(def, s string) string {
+		if s == "" {
+			return def
+		}
+		return s
+	},
+}
+
+// Templates formats notifications. The resolved templates are used once an
+// alert stopped firing, falling back to the title and body.
+type Templates struct {
+	title         *template.Template
+	body          *template.Template
+	resolvedTitle *template.Template
+	resolvedBody  *template.Template
+}
+
+// New parses the templates and executes them once against a sample alert,
+// so a misspelled field fails here rather than when an alert is sent. It
+// returns nil when every template is empty.
+func New(title, body, resolvedTitle, resolvedBody string) (*Templates, error) {
+	if title == "" && body == "" && resolvedTitle == "" && resolvedBody == "" {
+		return nil, nil
+	}
+	if title == "" && body == "" {
+		return nil, fmt.Errorf("a title or body template is required")
+	}
+
+	t := &Templates{}
+	for _, tmpl := range []struct {
+		name string
+		text string
+		dst  **template.Template
+	}{
+		{"title", title, &t.title},
+		{"body", body, &t.body},
+		{"resolved_title", resolvedTitle, &t.resolvedTitle},
+		{"resolved_body", resolvedBody, &t.resolvedBody},
+	} {
+		if tmpl.text == "" {
+			continue
+		}
+		parsed, err := template.New(tmpl.name).Funcs(funcs).Parse(tmpl.text)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", tmpl.name, err)
+		}
+		*tmpl.dst = parsed
+	}
+
+	sample := &Data{
+		Alert: types.Alert{
+			Level:    types.AlertLevelError,
+			Resource: types.ResourceTypePod,
+			Name:     "default/api",
+			Message:  "Container is in CrashLoopBackOff",
+			Labels:   map[string]string{types.LabelNamespace: "default"},
+			Annotations: map[string]string{
+				types.AnnotationDescription: "Back-off restarting failed container",
+			},
+			Cluster:  "sample",
+			StartsAt: time.Now(),
+		},
+		Status: StatusFiring,
+	}
+	if _, err := t.Render(sample); err != nil {
+		return nil, err
+	}
+	sample.Status = StatusResolved
+	sample.EndsAt = time.Now()
+	if _, err := t.Render(sample); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Render formats d as the title followed by the body on the next line
+func (t *Templates) Render(d *Data) (string, error) {
+	title, body := t.title, t.body
+	if d.Resolved() && (t.resolvedTitle != nil || t.resolvedBody != nil) {
+		title, body = t.resolvedTitle, t.resolvedBody
+	}
+
+	var parts []string
+	for _, tmpl := range []*template.Template{title, body} {
+		if tmpl == nil {
+			continue
+		}
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, d); err != nil {
+			return "", fmt.Errorf("template %s: %w", tmpl.Name(), err)
+		}
+		if b.Len() > 0 {
+			parts = append(parts, b.String())
+		}
+	}
+	return strings.Join(parts, "\n"), nil
+}
@@ -0,0 +1,97 @@
+package message
+
+import (
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+func TestTemplates_Render(t *testing.T) {
+	tmpl, err := New(
+		`{{ .Emoji }} {{ .Level | upper }} {{ .Name }}`,
+		`{{ .Message }} in {{ index .Labels "namespace" }}`,
+		`✅ {{ .Name }} resolved after {{ .Duration }}{{ if .AckedBy }}, acknowledged by {{ .AckedBy }}{{ end }}`,
+		"",
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	start := time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC)
+	d := &Data{
+		Alert: types.Alert{
+			Level:    types.AlertLevelCritical,
+			Resource: types.ResourceTypePod,
+			Name:     "default/api",
+			Message:  "Container is in CrashLoopBackOff",
+			Labels:   map[string]string{types.LabelNamespace: "default"},
+			StartsAt: start,
+		},
+		Status: StatusFiring,
+	}
+
+	got, err := tmpl.Render(d)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if expected := "🚨 CRITICAL default/api\nContainer is in CrashLoopBackOff in default"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	d.Status = StatusResolved
+	d.EndsAt = start.Add(25 * time.Minute)
+	d.AckedBy = "alice"
+	got, err = tmpl.Render(d)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if expected := "✅ default/api resolved after 25m0s, acknowledged by alice"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		body  string
+		extra string
+	}{
+		{name: "Syntax error", title: `{{ .Name `},
+		{name: "Misspelled field", title: `{{ .Nmae }}`},
+		{name: "Unknown function", body: `{{ shout .Name }}`},
+		{name: "Only a resolved template", extra: `{{ .Name }} resolved`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.title, tt.body, tt.extra, ""); err == nil {
+				t.Error("Expected New to fail")
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	d := &Data{
+		Alert: types.Alert{
+			Level:    types.AlertLevelError,
+			Resource: types.ResourceTypeNode,
+			Name:     "rpi-1",
+			Message:  "Node is not ready",
+		},
+		Status: StatusFiring,
+	}
+	if got := Default(d); got != "❌ [node] rpi-1: Node is not ready" {
+		t.Errorf("Unexpected firing message %q", got)
+	}
+
+	d.Status = StatusResolved
+	d.StartsAt = time.Now().Add(-time.Hour)
+	d.EndsAt = d.StartsAt.Add(30 * time.Minute)
+	d.AckedBy = "alice"
+	if got := Default(d); got != "✅ [node] rpi-1: Resolved after 30m0s, acknowledged by alice" {
+		t.Errorf("Unexpected resolved message %q", got)
+	}
+}
@@ -0,0 +1,238 @@
+// Package silence keeps the silences that mute matching alerts until they
+// expire, persisted to a JSON file so they survive restarts
+package silence
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/matcher"
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+// expired silences are kept this long so they still show up in listings
+const retention = 24 * time.Hour
+
+// ErrNotFound is returned when no silence has the given ID
+var ErrNotFound = errors.New("silence not found")
+
+// Silence mutes the alerts matching all of its matchers between StartsAt
+// and EndsAt
+type Silence struct {
+	ID        string    `json:"id"`
+	Matchers  []string  `json:"matchers"`
+	CreatedBy string    `json:"created_by"`
+	Comment   string    `json:"comment"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+
+	matchers []matcher.Matcher
+}
+
+// Active reports whether the silence mutes alerts at now
+func (s *Silence) Active(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+func (s *Silence) compile() error {
+	if len(s.Matchers) == 0 {
+		return errors.New("a silence needs at least one matcher")
+	}
+	matchers, err := matcher.ParseAll(s.Matchers)
+	if err != nil {
+		return err
+	}
+	s.matchers = matchers
+	return nil
+}
+
+// Store holds the silences, writing them to path on every change. An empty
+// path keeps them in memory only.
+type Store struct {
+	path     string
+	silences map[string]*Silence
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+// NewStore loads the silences saved at path, a missing file is an empty store
+func NewStore(path string) (*Store, error) {
+	s := &Store{
+		path:     path,
+		silences: make(map[string]*Silence),
+		now:      time.Now,
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read silences: %w", err)
+	}
+
+	var saved []*Silence
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse silences in %s: %w", path, err)
+	}
+	for _, sil := range saved {
+		if err := sil.compile(); err != nil {
+			return nil, fmt.Errorf("silence %s: %w", sil.ID, err)
+		}
+		s.silences[sil.ID] = sil
+	}
+	return s, nil
+}
+
+// Add validates and stores a new silence, starting now unless StartsAt is set
+func (s *Store) Add(sil Silence) (Silence, error) {
+	if err := sil.compile(); err != nil {
+		return Silence{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if sil.StartsAt.IsZero() {
+		sil.StartsAt = now
+	}
+	if !sil.EndsAt.After(sil.StartsAt) {
+		return Silence{}, errors.New("a silence has to end after it starts")
+	}
+	if !sil.EndsAt.After(now) {
+		return Silence{}, errors.New("a silence has to end in the future")
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Silence{}, err
+	}
+	sil.ID = id
+	s.silences[id] = &sil
+
+	if err := s.save(); err != nil {
+		delete(s.silences, id)
+		return Silence{}, err
+	}
+	return sil, nil
+}
+
+// Expire ends a silence now
+func (s *Store) Expire(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sil, ok := s.silences[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	now := s.now()
+	if !sil.EndsAt.After(now) {
+		return nil
+	}
+	endsAt := sil.EndsAt
+	sil.EndsAt = now
+	if sil.StartsAt.After(now) {
+		sil.StartsAt = now
+	}
+	if err := s.save(); err != nil {
+		sil.EndsAt = endsAt
+		return err
+	}
+	return nil
+}
+
+// List returns the silences that have not ended yet or ended recently,
+// ordered by start time
+func (s *Store) List() []Silence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Silence, 0, len(s.silences))
+	for _, sil := range s.silences {
+		list = append(list, *sil)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].StartsAt.Equal(list[j].StartsAt) {
+			return list[i].StartsAt.Before(list[j].StartsAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// Silenced reports whether an active silence matches alert
+func (s *Store) Silenced(alert types.Alert) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for _, sil := range s.silences {
+		if sil.Active(now) && matcher.MatchesAll(sil.matchers, alert) {
+			return true
+		}
+	}
+	return false
+}
+
+// save drops silences past retention and writes the rest, the caller holds mu
+func (s *Store) save() error {
+	now := s.now()
+	list := make([]*Silence, 0, len(s.silences))
+	for id, sil := range s.silences {
+		if now.Sub(sil.EndsAt) > retention {
+			delete(s.silences, id)
+			continue
+		}
+		list = append(list, sil)
+	}
+	if s.path == "" {
+		return nil
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal silences: %w", err)
+	}
+
+	// write to a temporary file first so a crash never leaves half a file
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save silences: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save silences: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save silences: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save silences: %w", err)
+	}
+	return nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate silence id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,157 @@
+package silence
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/types"
+)
+
+func TestStore_Silenced(t *testing.T) {
+	s, err := NewStore("")
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	sil, err := s.Add(Silence{
+		Matchers:  []string{`resource="node"`, `name=~"rpi-.*"`},
+		CreatedBy: "alice",
+		Comment:   "replacing the SD card",
+		EndsAt:    time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		alert    types.Alert
+		expected bool
+	}{
+		{
+			name:     "Matching node",
+			alert:    types.Alert{Level: types.AlertLevelCritical, Resource: types.ResourceTypeNode, Name: "rpi-1"},
+			expected: true,
+		},
+		{
+			name:     "Other node",
+			alert:    types.Alert{Level: types.AlertLevelCritical, Resource: types.ResourceTypeNode, Name: "nuc-1"},
+			expected: false,
+		},
+		{
+			name:     "Other resource",
+			alert:    types.Alert{Level: types.AlertLevelError, Resource: types.ResourceTypePod, Name: "rpi-1"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Silenced(tt.alert); got != tt.expected {
+				t.Errorf("Expected silenced %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if err := s.Expire(sil.ID); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if s.Silenced(tests[0].alert) {
+		t.Error("Expected an expired silence to stop muting")
+	}
+	if err := s.Expire("missing"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStore_Add_Invalid(t *testing.T) {
+	s, _ := NewStore("")
+
+	tests := []struct {
+		name    string
+		silence Silence
+	}{
+		{
+			name:    "No matchers",
+			silence: Silence{EndsAt: time.Now().Add(time.Hour)},
+		},
+		{
+			name:    "Invalid matcher",
+			silence: Silence{Matchers: []string{"node"}, EndsAt: time.Now().Add(time.Hour)},
+		},
+		{
+			name:    "Ends in the past",
+			silence: Silence{Matchers: []string{`node="rpi-1"`}, EndsAt: time.Now().Add(-time.Hour)},
+		},
+		{
+			name: "Ends before it starts",
+			silence: Silence{
+				Matchers: []string{`node="rpi-1"`},
+				StartsAt: time.Now().Add(2 * time.Hour),
+				EndsAt:   time.Now().Add(time.Hour),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Add(tt.silence); err == nil {
+				t.Error("Expected Add to fail")
+			}
+		})
+	}
+}
+
+func TestStore_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "silences.json")
+
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	sil, err := s.Add(Silence{
+		Matchers:  []string{`node="rpi-1"`},
+		CreatedBy: "alice",
+		EndsAt:    time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	reloaded, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore failed on reload: %v", err)
+	}
+	list := reloaded.List()
+	if len(list) != 1 || list[0].ID != sil.ID || list[0].CreatedBy != "alice" {
+		t.Fatalf("Expected the silence to survive a restart, got %+v", list)
+	}
+
+	alert := types.Alert{Resource: types.ResourceTypePod, Labels: map[string]string{types.LabelNode: "rpi-1"}}
+	if !reloaded.Silenced(alert) {
+		t.Error("Expected the reloaded silence to mute alerts")
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	s, _ := NewStore("")
+	sil, err := s.Add(Silence{Matchers: []string{`node="rpi-1"`}, EndsAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := s.Expire(sil.ID); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+	if len(s.List()) != 1 {
+		t.Fatal("Expected a just expired silence to be listed")
+	}
+
+	s.now = func() time.Time { return time.Now().Add(2 * retention) }
+	if _, err := s.Add(Silence{Matchers: []string{`node="rpi-2"`}, EndsAt: s.now().Add(time.Hour)}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if list := s.List(); len(list) != 1 || list[0].ID == sil.ID {
+		t.Errorf("Expected the old silence to be dropped, got %+v", list)
+	}
+}
@@ -1,6 +1,11 @@
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+)
 
 // alert struct
 type Alert struct {
@@ -8,6 +13,26 @@ type Alert struct {
 	Resource string `json:"resource"`
 	Name     string `json:"name"`
 	Message  string `json:"message"`
+	// notifier route the alert is sent to, empty for the default one
+	Route string `json:"route,omitempty"`
+	// e.g. namespace, node and owner, used by inhibit rules and silences
+	Labels map[string]string `json:"labels,omitempty"`
+	// e.g. runbook URL and description, not part of the fingerprint
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// name of the cluster the alert comes from
+	Cluster string `json:"cluster,omitempty"`
+	// when the alert started firing and, once resolved, stopped
+	StartsAt time.Time `json:"starts_at,omitzero"`
+	EndsAt   time.Time `json:"ends_at,omitzero"`
+}
+
+// FiringAlert is an alert that is currently firing
+type FiringAlert struct {
+	// identifies the alert, e.g. to acknowledge it
+	Fingerprint string `json:"fingerprint"`
+	Alert       Alert  `json:"alert"`
+	AckedBy     string `json:"acked_by,omitempty"`
+	AckComment  string `json:"ack_comment,omitempty"`
 }
 
 // alert level
@@ -18,14 +43,98 @@ const (
 	AlertLevelInfo     = "info"
 )
 
+// ValidLevel reports whether level is one of the alert levels
+func ValidLevel(level string) bool {
+	switch level {
+	case AlertLevelCritical, AlertLevelError, AlertLevelWarning, AlertLevelInfo:
+		return true
+	}
+	return false
+}
+
 // resource type
 const (
 	ResourceTypePod        = "pod"
 	ResourceTypeNode       = "node"
 	ResourceTypeDeployment = "deployment"
 	ResourceTypeService    = "service"
+	ResourceTypeDaemonSet  = "daemonset"
+	ResourceTypeJob        = "job"
+	ResourceTypeCronJob    = "cronjob"
+	ResourceTypePVC        = "pvc"
+	ResourceTypePV         = "pv"
+	ResourceTypeEvent      = "event"
+	ResourceTypeHPA        = "hpa"
+	ResourceTypeQuota      = "resourcequota"
+	ResourceTypePDB        = "pdb"
+	ResourceTypeSecret     = "secret"
+	ResourceTypeIngress    = "ingress"
+	// summaries of maintenance windows, not a cluster resource
+	ResourceTypeMaintenance = "maintenance"
 )
 
+// label names. Level, resource, name and cluster are read from the alert
+// fields.
+const (
+	LabelLevel     = "level"
+	LabelResource  = "resource"
+	LabelName      = "name"
+	LabelCluster   = "cluster"
+	LabelNamespace = "namespace"
+	LabelNode      = "node"
+	// workload owning the object, e.g. "default/deployment/api"
+	LabelOwner = "owner"
+	// which check raised the alert, when one object can raise several
+	// alerts of the same level, so each gets its own fingerprint
+	LabelCheck = "check"
+	// reason of the Kubernetes event an event alert forwards
+	LabelReason = "reason"
+)
+
+// annotation names
+const (
+	AnnotationDescription = "description"
+	AnnotationRunbookURL  = "runbook_url"
+)
+
+// Label returns the value of a label, including the level, resource, name
+// and cluster of the alert
+func (a *Alert) Label(name string) string {
+	switch name {
+	case LabelLevel:
+		return a.Level
+	case LabelResource:
+		return a.Resource
+	case LabelName:
+		return a.Name
+	case LabelCluster:
+		return a.Cluster
+	}
+	return a.Labels[name]
+}
+
+// Fingerprint identifies an alert across evaluations. It is a hash of the
+// level, resource, name, cluster and labels, so a change in message or
+// annotations keeps it while a change in level makes a new alert.
+func (a *Alert) Fingerprint() string {
+	names := make([]string, 0, len(a.Labels))
+	for name := range a.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := fnv.New64a()
+	for _, part := range []string{a.Level, a.Resource, a.Name, a.Cluster} {
+		h.Write([]byte(part))
+		h.Write([]byte{0xff})
+	}
+	for _, name := range names {
+		fmt.Fprintf(h, "%s=%s", name, a.Labels[name])
+		h.Write([]byte{0xff})
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
 func (a *Alert) GetEmoji() string {
 	emojiMap := map[string]string{
 		AlertLevelWarning:  "⚠️",
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/5iing/k8s-reliablity-informer/pkg/api"
+	"github.com/5iing/k8s-reliablity-informer/pkg/config"
+	"github.com/5iing/k8s-reliablity-informer/pkg/silence"
+)
+
+const silenceUsage = `Usage:
+  k3s-health-checker silence add [-api URL] [-duration 2h] [-author NAME] [-comment TEXT] MATCHER...
+  k3s-health-checker silence list [-api URL]
+  k3s-health-checker silence expire [-api URL] ID...
+
+Matchers are written like 'node="rpi-1"' or 'name=~"rpi-.*"'. The API token,
+if the checker requires one, is read from $RELIABILITY_INFORMER_API_TOKEN.
+`
+
+// runSilence manages silences through the API of a running checker and
+// returns the exit code
+func runSilence(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, silenceUsage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("silence "+args[0], flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, silenceUsage) }
+	apiURL := fs.String("api", "http://localhost:8080", "address of the checker API")
+
+	var err error
+	switch args[0] {
+	case "add":
+		duration := fs.Duration("duration", 2*time.Hour, "how long the silence lasts")
+		author := fs.String("author", os.Getenv("USER"), "who created the silence")
+		comment := fs.String("comment", "", "why the alerts are silenced")
+		if fs.Parse(args[1:]) != nil {
+			return 2
+		}
+		err = addSilence(*apiURL, api.SilenceRequest{
+			Matchers:  fs.Args(),
+			CreatedBy: *author,
+			Comment:   *comment,
+			Duration:  duration.String(),
+		})
+	case "list":
+		if fs.Parse(args[1:]) != nil {
+			return 2
+		}
+		err = listSilences(*apiURL)
+	case "expire":
+		if fs.Parse(args[1:]) != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			fs.Usage()
+			return 2
+		}
+		for _, id := range fs.Args() {
+			if err = expireSilence(*apiURL, id); err != nil {
+				break
+			}
+			fmt.Printf("Expired silence %s\n", id)
+		}
+	default:
+		fs.Usage()
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func addSilence(apiURL string, req api.SilenceRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	var created silence.Silence
+	if err := callAPI(http.MethodPost, apiURL+"/api/silences", bytes.NewReader(body), &created); err != nil {
+		return err
+	}
+	fmt.Printf("Created silence %s until %s\n", created.ID, created.EndsAt.Local().Format(time.RFC3339))
+	return nil
+}
+
+func listSilences(apiURL string) error {
+	var silences []silence.Silence
+	if err := callAPI(http.MethodGet, apiURL+"/api/silences", nil, &silences); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATE\tENDS\tCREATED BY\tMATCHERS\tCOMMENT")
+	for _, s := range silences {
+		state := "active"
+		if !s.EndsAt.After(now) {
+			state = "expired"
+		} else if s.StartsAt.After(now) {
+			state = "pending"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.ID, state, s.EndsAt.Local().Format(time.RFC3339), s.CreatedBy,
+			strings.Join(s.Matchers, ","), s.Comment)
+	}
+	return w.Flush()
+}
+
+func expireSilence(apiURL, id string) error {
+	return callAPI(http.MethodDelete, apiURL+"/api/silences/"+id, nil, nil)
+}
+
+// callAPI sends a request, with the API token from the environment if set,
+// and decodes the JSON response into out
+func callAPI(method, url string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token := os.Getenv(config.APITokenEnv); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the checker API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("%s (status %d)", apiErr.Error, resp.StatusCode)
+		}
+		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}